@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/smallcase/kube2cdk8s/pkg/kube2cdk8s"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func languageCommand(use string, language kube2cdk8s.Language) *cobra.Command {
+	command := &cobra.Command{
+		Use:  use,
+		Long: fmt.Sprintf("convert k8s yaml to %s", language),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filePaths := viper.GetStringSlice("file")
+
+			opts := kube2cdk8s.Options{
+				Language:          language,
+				Jobs:              viper.GetInt("jobs"),
+				ImportAlias:       viper.GetString("import-alias"),
+				ImportPath:        viper.GetString("import-path"),
+				Modules:           modules(viper.GetStringMapString("crd-module")),
+				APIObjects:        viper.GetStringSlice("api-object"),
+				KubernetesVersion: viper.GetString("k8s-version"),
+				Target:            kube2cdk8s.Target(viper.GetString("target")),
+				IDStrategy:        viper.GetString("id-strategy"),
+				Variables:         viper.GetBool("variables"),
+				Params:            viper.GetStringSlice("params"),
+			}
+
+			var constructs []kube2cdk8s.Construct
+
+			helmChart, kustomization := viper.GetString("helm-chart"), viper.GetString("kustomize")
+			if helmChart != "" && kustomization != "" {
+				return fmt.Errorf("--helm-chart and --kustomize cannot be used together")
+			}
+
+			// the chart declares the props the constructs read
+			if len(opts.Params) > 0 && !viper.GetBool("chart") {
+				return fmt.Errorf("--params requires --chart")
+			}
+
+			if kustomization != "" {
+				if len(filePaths) > 0 {
+					return fmt.Errorf("-f cannot be used with --kustomize")
+				}
+
+				var err error
+				constructs, err = kube2cdk8s.ConvertKustomization(cmd.Context(), kube2cdk8s.Kustomization{Path: kustomization}, opts)
+				if err != nil {
+					return err
+				}
+			} else if helmChart != "" {
+				var err error
+				constructs, err = kube2cdk8s.ConvertHelmChart(cmd.Context(), kube2cdk8s.HelmChart{
+					Path:        helmChart,
+					ReleaseName: viper.GetString("release-name"),
+					ValuesFiles: filePaths,
+					Set:         viper.GetStringSlice("set"),
+				}, opts)
+				if err != nil {
+					return err
+				}
+			} else if len(filePaths) == 0 || (len(filePaths) == 1 && filePaths[0] == "-") {
+				if err := checkStdin(len(filePaths) == 0); err != nil {
+					return err
+				}
+
+				var err error
+				constructs, err = kube2cdk8s.Convert(cmd.Context(), os.Stdin, opts)
+				if err != nil {
+					return fmt.Errorf("stdin: %w", err)
+				}
+			} else {
+				files, err := kube2cdk8s.FindFiles(filePaths, viper.GetBool("recursive"))
+				if err != nil {
+					return err
+				}
+
+				constructs, err = kube2cdk8s.ConvertFiles(cmd.Context(), files, opts)
+				if err != nil {
+					return err
+				}
+			}
+
+			if len(constructs) == 0 {
+				return fmt.Errorf("no kubernetes object found")
+			}
+
+			reportUnsupported(constructs)
+
+			if viper.GetBool("chart") {
+				chart, err := kube2cdk8s.Chart(viper.GetString("chart-name"), constructs, opts)
+				if err != nil {
+					return err
+				}
+				fmt.Print(chart)
+				return nil
+			}
+
+			fmt.Print(kube2cdk8s.Join(constructs, language))
+			return nil
+		}}
+
+	return command
+}
+
+// modules parses the group=alias:path values of --crd-module.
+func modules(values map[string]string) map[string]kube2cdk8s.Module {
+	modules := map[string]kube2cdk8s.Module{}
+	for group, value := range values {
+		alias, path := value, ""
+		if i := strings.Index(value, ":"); i >= 0 {
+			alias, path = value[:i], value[i+1:]
+		}
+		modules[group] = kube2cdk8s.Module{Alias: alias, Path: path}
+	}
+
+	return modules
+}
+
+// reportUnsupported warns about the objects that were not generated with
+// cdk8s-plus and the fields that prevented it.
+func reportUnsupported(constructs []kube2cdk8s.Construct) {
+	for _, c := range constructs {
+		fields := c.Unsupported()
+		if len(fields) == 0 {
+			continue
+		}
+
+		object := c.Kind + " " + c.Name
+		if c.Source != "" {
+			object = c.Source + ": " + object
+		}
+		fmt.Fprintf(os.Stderr, "%s: kept as a k8s construct, cdk8s-plus cannot express %s\n", object, strings.Join(fields, ", "))
+	}
+}
+
+// checkStdin makes sure the manifest is piped in when it is read from stdin.
+// Without a file, a terminal on stdin means the user forgot -f.
+func checkStdin(noFile bool) error {
+	stat, err := os.Stdin.Stat()
+	if err != nil {
+		return err
+	}
+
+	if noFile && stat.Mode()&os.ModeCharDevice != 0 {
+		log.Fatal("-f, --file is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,10 @@
+package cmd
+
+import (
+	"github.com/smallcase/kube2cdk8s/pkg/kube2cdk8s"
+	"github.com/spf13/cobra"
+)
+
+func CSharpCommand() *cobra.Command {
+	return languageCommand("csharp", kube2cdk8s.CSharp)
+}
@@ -0,0 +1,10 @@
+package cmd
+
+import (
+	"github.com/smallcase/kube2cdk8s/pkg/kube2cdk8s"
+	"github.com/spf13/cobra"
+)
+
+func JavaCommand() *cobra.Command {
+	return languageCommand("java", kube2cdk8s.Java)
+}
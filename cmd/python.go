@@ -0,0 +1,10 @@
+package cmd
+
+import (
+	"github.com/smallcase/kube2cdk8s/pkg/kube2cdk8s"
+	"github.com/spf13/cobra"
+)
+
+func PythonCommand() *cobra.Command {
+	return languageCommand("python", kube2cdk8s.Python)
+}
@@ -4,8 +4,9 @@ go 1.16
 
 require (
 	github.com/bradleyjkemp/cupaloy v2.3.0+incompatible
-	github.com/pulumi/kube2pulumi v0.0.11
 	github.com/spf13/cobra v1.0.0
 	github.com/spf13/viper v1.8.1
-	golang.org/x/net v0.0.0-20210520170846-37e1c6afe023 // indirect
+	golang.org/x/sys v0.0.0-20210817190340-bfb29a6856f2 // indirect
+	golang.org/x/text v0.3.6 // indirect
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -21,35 +21,183 @@ import (
 	"os"
 
 	"github.com/smallcase/kube2cdk8s/cmd"
+	"github.com/smallcase/kube2cdk8s/pkg/kube2cdk8s"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
-	manifestFile string
-	multiple     bool
+	manifestFiles []string
+	recursive     bool
+	multiple      bool
+	chart         bool
+	helmChart     string
+	helmSet       []string
+	releaseName   string
+	kustomize     string
+	chartName     string
+	importAlias   string
+	importPath    string
+	crdModules    map[string]string
+	apiObjects    []string
+	k8sVersion    string
+	target        string
+	idStrategy    string
+	variables     bool
+	params        []string
+	jobs          int
+	configFile    string
 )
 
 func configureCLI() *cobra.Command {
 	rootCmd := &cobra.Command{Use: "kube2cdk8s", Long: "converts k8s yaml to cdk8s"}
 
 	rootCmd.AddCommand(cmd.TSCommand())
+	rootCmd.AddCommand(cmd.PythonCommand())
+	rootCmd.AddCommand(cmd.GoCommand())
+	rootCmd.AddCommand(cmd.JavaCommand())
+	rootCmd.AddCommand(cmd.CSharpCommand())
 
-	rootCmd.PersistentFlags().StringVarP(&manifestFile, "file", "f", "", "YAML file to convert")
+	rootCmd.PersistentFlags().StringArrayVarP(&manifestFiles, "file", "f", nil, "YAML file, directory or glob to convert, can be repeated, - or no file reads from stdin")
 	err := viper.BindPFlag("file", rootCmd.PersistentFlags().Lookup("file"))
 	if err != nil {
 		log.Println(err)
 	}
 
+	rootCmd.PersistentFlags().BoolVarP(&recursive, "recursive", "R", false, "convert directories given with -f recursively")
+	err = viper.BindPFlag("recursive", rootCmd.PersistentFlags().Lookup("recursive"))
+	if err != nil {
+		log.Println(err)
+	}
+
 	rootCmd.PersistentFlags().BoolVarP(&multiple, "multiple", "m", false, "convert multiple yamls seperated by ---")
-	err = viper.BindPFlag("multiple", rootCmd.PersistentFlags().Lookup("multiple"))
+	err = rootCmd.PersistentFlags().MarkDeprecated("multiple", "multiple documents are detected automatically")
+	if err != nil {
+		log.Println(err)
+	}
+
+	rootCmd.PersistentFlags().StringVar(&helmChart, "helm-chart", "", "local Helm chart to render with helm template and convert, -f then gives its values files")
+	err = viper.BindPFlag("helm-chart", rootCmd.PersistentFlags().Lookup("helm-chart"))
+	if err != nil {
+		log.Println(err)
+	}
+
+	rootCmd.PersistentFlags().StringArrayVar(&helmSet, "set", nil, "key=value to override in the values of --helm-chart, can be repeated")
+	err = viper.BindPFlag("set", rootCmd.PersistentFlags().Lookup("set"))
+	if err != nil {
+		log.Println(err)
+	}
+
+	rootCmd.PersistentFlags().StringVar(&releaseName, "release-name", kube2cdk8s.DefaultReleaseName, "release name --helm-chart is rendered with")
+	err = viper.BindPFlag("release-name", rootCmd.PersistentFlags().Lookup("release-name"))
+	if err != nil {
+		log.Println(err)
+	}
+
+	rootCmd.PersistentFlags().StringVar(&kustomize, "kustomize", "", "kustomization directory to build with kustomize, or kubectl kustomize, and convert")
+	err = viper.BindPFlag("kustomize", rootCmd.PersistentFlags().Lookup("kustomize"))
+	if err != nil {
+		log.Println(err)
+	}
+
+	rootCmd.PersistentFlags().BoolVar(&chart, "chart", false, "emit a complete cdk8s chart file instead of bare constructs")
+	err = viper.BindPFlag("chart", rootCmd.PersistentFlags().Lookup("chart"))
+	if err != nil {
+		log.Println(err)
+	}
+
+	rootCmd.PersistentFlags().StringVar(&chartName, "chart-name", kube2cdk8s.DefaultChartName, "name of the chart generated with --chart, my-app gives MyAppChart")
+	err = viper.BindPFlag("chart-name", rootCmd.PersistentFlags().Lookup("chart-name"))
+	if err != nil {
+		log.Println(err)
+	}
+
+	rootCmd.PersistentFlags().StringVar(&importAlias, "import-alias", kube2cdk8s.DefaultImportAlias, "name the generated cdk8s bindings are imported as")
+	err = viper.BindPFlag("import-alias", rootCmd.PersistentFlags().Lookup("import-alias"))
+	if err != nil {
+		log.Println(err)
+	}
+
+	rootCmd.PersistentFlags().StringVar(&importPath, "import-path", "", "module the generated cdk8s bindings are imported from with --chart, defaults to where cdk8s import writes them")
+	err = viper.BindPFlag("import-path", rootCmd.PersistentFlags().Lookup("import-path"))
+	if err != nil {
+		log.Println(err)
+	}
+
+	rootCmd.PersistentFlags().StringToStringVar(&crdModules, "crd-module", nil, "module of the custom resources of an API group as group=alias or group=alias:path, or group=ApiObject to emit plain ApiObjects, can be repeated")
+	err = viper.BindPFlag("crd-module", rootCmd.PersistentFlags().Lookup("crd-module"))
+	if err != nil {
+		log.Println(err)
+	}
+
+	rootCmd.PersistentFlags().StringSliceVar(&apiObjects, "api-object", nil, "kind to emit as a plain ApiObject, as Kind or group/Kind, can be repeated; kinds missing from the k8s bindings always are")
+	err = viper.BindPFlag("api-object", rootCmd.PersistentFlags().Lookup("api-object"))
+	if err != nil {
+		log.Println(err)
+	}
+
+	rootCmd.PersistentFlags().StringVar(&target, "target", string(kube2cdk8s.TargetK8s), "constructs to generate: k8s for the cdk8s import bindings, plus for cdk8s-plus where it can express the object")
+	err = viper.BindPFlag("target", rootCmd.PersistentFlags().Lookup("target"))
+	if err != nil {
+		log.Println(err)
+	}
+
+	rootCmd.PersistentFlags().StringVar(&idStrategy, "id-strategy", kube2cdk8s.IDName, "construct IDs: name, kind-name, namespace-kind-name or a Go template such as {{.Namespace}}-{{.Name}}")
+	err = viper.BindPFlag("id-strategy", rootCmd.PersistentFlags().Lookup("id-strategy"))
+	if err != nil {
+		log.Println(err)
+	}
+
+	rootCmd.PersistentFlags().BoolVar(&variables, "variables", false, "assign every construct to a variable named after its kind and name, e.g. apiDeployment")
+	err = viper.BindPFlag("variables", rootCmd.PersistentFlags().Lookup("variables"))
 	if err != nil {
 		log.Println(err)
 	}
 
+	rootCmd.PersistentFlags().StringSliceVar(&params, "params", nil, "values lifted into props of the chart: namespace, replicas, image, tag, limits, requests or name=selector, typescript only")
+	err = viper.BindPFlag("params", rootCmd.PersistentFlags().Lookup("params"))
+	if err != nil {
+		log.Println(err)
+	}
+
+	rootCmd.PersistentFlags().StringVar(&k8sVersion, "k8s-version", "", "version of kubernetes the k8s bindings were imported for, e.g. 1.22, to reject API versions it does not serve")
+	err = viper.BindPFlag("k8s-version", rootCmd.PersistentFlags().Lookup("k8s-version"))
+	if err != nil {
+		log.Println(err)
+	}
+
+	rootCmd.PersistentFlags().IntVarP(&jobs, "jobs", "j", 0, "number of documents converted in parallel, defaults to the number of CPUs")
+	err = viper.BindPFlag("jobs", rootCmd.PersistentFlags().Lookup("jobs"))
+	if err != nil {
+		log.Println(err)
+	}
+
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "config file, defaults to .kube2cdk8s.yaml in the current or home directory")
+	cobra.OnInitialize(initConfig)
+
 	return rootCmd
 }
 
+// initConfig reads the config file, whose keys are the names of the flags.
+// Flags given on the command line take precedence over it.
+func initConfig() {
+	if configFile != "" {
+		viper.SetConfigFile(configFile)
+	} else {
+		viper.SetConfigName(".kube2cdk8s")
+		viper.AddConfigPath(".")
+		if home, err := os.UserHomeDir(); err == nil {
+			viper.AddConfigPath(home)
+		}
+	}
+
+	if err := viper.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			log.Fatalf("unable to read config file: %v", err)
+		}
+	}
+}
+
 func main() {
 	rootCmd := configureCLI()
 	if err := rootCmd.Execute(); err != nil {
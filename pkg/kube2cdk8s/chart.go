@@ -0,0 +1,435 @@
+package kube2cdk8s
+
+import (
+	"fmt"
+	"go/format"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DefaultChartName is the chart name used when none is given.
+const DefaultChartName = "my-chart"
+
+// Join joins the code of constructs, separated by a blank line. When the
+// constructs were read from more than one file, the constructs of each file
+// are preceded by a comment naming it.
+func Join(constructs []Construct, language Language) string {
+	var b strings.Builder
+
+	labelSources := false
+	for _, c := range constructs {
+		if c.Source != constructs[0].Source {
+			labelSources = true
+			break
+		}
+	}
+
+	for i, c := range constructs {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if labelSources && (i == 0 || constructs[i-1].Source != c.Source) {
+			b.WriteString(language.Comment(c.Source) + "\n")
+		}
+		b.WriteString(c.Code)
+	}
+
+	return b.String()
+}
+
+// chartFile holds what a chart template needs to render a chart.
+type chartFile struct {
+	// class is the name of the chart class, e.g. MyChart.
+	class string
+
+	// id is the construct id the chart is created with in the app.
+	id string
+
+	// body is the code of the constructs, not indented.
+	body string
+
+	// modules are the bindings the constructs are imported from.
+	modules []Module
+
+	// cdk8s are the cdk8s core classes imported besides App and Chart.
+	cdk8s []string
+
+	// params are the props of the chart the constructs read.
+	params []Param
+
+	// unread are the variables of the constructs that no construct reads.
+	unread []string
+}
+
+var chartTemplates = map[Language]func(c chartFile) string{
+	TypeScript: tsChart,
+	Python:     pyChart,
+	Go:         goChart,
+	Java:       javaChart,
+	CSharp:     csChart,
+}
+
+// Chart renders constructs as a complete cdk8s program in opts.Language: the
+// imports, a chart class creating the constructs in its constructor and the
+// App that synthesizes it. The class is named after name, so my-app gives
+// MyAppChart, and name is also the id of the chart.
+func Chart(name string, constructs []Construct, opts Options) (string, error) {
+	language := opts.Language
+	if language == "" {
+		language = TypeScript
+	}
+
+	template, ok := chartTemplates[language]
+	if !ok {
+		return "", fmt.Errorf("unsupported language: %s", language)
+	}
+
+	if name == "" {
+		name = DefaultChartName
+	}
+
+	class, err := chartClass(name)
+	if err != nil {
+		return "", err
+	}
+
+	if err := opts.validate(); err != nil {
+		return "", err
+	}
+
+	var modules []Module
+	var params []Param
+	var unread []string
+	cdk8s := map[string]bool{}
+	seen := map[Module]bool{}
+	for _, c := range constructs {
+		for _, p := range c.Params() {
+			existing, ok := findParam(params, p.Name)
+			if !ok {
+				params = append(params, p)
+			} else if existing != p {
+				return "", fmt.Errorf("param %s defaults to both %s and %s", p.Name, existing.Default, p.Default)
+			}
+		}
+		if c.module.Alias != "" && !seen[c.module] {
+			seen[c.module] = true
+			modules = append(modules, c.module)
+		}
+		for _, name := range c.cdk8s {
+			cdk8s[name] = true
+		}
+		if c.Variable != "" && !c.read {
+			unread = append(unread, c.Variable)
+		}
+	}
+
+	if len(params) > 0 && language != TypeScript {
+		return "", fmt.Errorf("params are only supported in %s", TypeScript)
+	}
+
+	chart := template(chartFile{
+		class:   class,
+		id:      name,
+		body:    Join(constructs, language),
+		modules: modules,
+		cdk8s:   sortedKeys(cdk8s),
+		params:  params,
+		unread:  unread,
+	})
+
+	if language == Go {
+		formatted, err := format.Source([]byte(chart))
+		if err != nil {
+			return "", err
+		}
+		chart = string(formatted)
+	}
+
+	return chart, nil
+}
+
+// chartClass returns the class name of the chart called name, the words of
+// name in PascalCase ending with Chart.
+func chartClass(name string) (string, error) {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return "", fmt.Errorf("invalid chart name %q", name)
+	}
+
+	var class string
+	for _, w := range words {
+		class += upperFirst(w)
+	}
+
+	if unicode.IsDigit(rune(class[0])) {
+		return "", fmt.Errorf("invalid chart name %q: must start with a letter", name)
+	}
+
+	if !strings.HasSuffix(class, "Chart") {
+		class += "Chart"
+	}
+
+	return class, nil
+}
+
+// indentCode indents every line of code by level, except the lines inside
+// backquoted strings, whose content would change otherwise.
+func indentCode(code string, level int) string {
+	var b strings.Builder
+	var inString rune
+	escaped, comment := false, false
+
+	for i, c := range code {
+		if (i == 0 || code[i-1] == '\n') && c != '\n' && inString != '`' {
+			b.WriteString(indent(level))
+		}
+		b.WriteRune(c)
+
+		switch {
+		case c == '\n':
+			// only backquoted strings span lines
+			comment = false
+			if inString == '"' {
+				inString = 0
+			}
+		case comment:
+		case escaped:
+			escaped = false
+		case c == '\\' && inString != 0:
+			escaped = true
+		case inString == 0 && (c == '"' || c == '`'):
+			inString = c
+		case inString == 0 && (c == '#' || strings.HasPrefix(code[i:], "//")):
+			comment = true
+		case c == inString:
+			inString = 0
+		}
+	}
+
+	return b.String()
+}
+
+// cdk8sImports returns the cdk8s core classes the chart imports.
+func (c chartFile) cdk8sImports() []string {
+	names := map[string]bool{"App": true, "Chart": true}
+	for _, name := range c.cdk8s {
+		names[name] = true
+	}
+
+	return sortedKeys(names)
+}
+
+func tsChart(c chartFile) string {
+	var b strings.Builder
+
+	b.WriteString("import { Construct } from 'constructs';\n")
+	fmt.Fprintf(&b, "import { %s } from 'cdk8s';\n", strings.Join(c.cdk8sImports(), ", "))
+	for _, m := range c.modules {
+		fmt.Fprintf(&b, "import * as %s from '%s';\n", m.Alias, m.Path)
+	}
+	b.WriteString("\n")
+	if len(c.params) > 0 {
+		fmt.Fprintf(&b, "export interface %sProps {\n", c.class)
+		for _, p := range c.params {
+			fmt.Fprintf(&b, indent(1)+"readonly %s?: %s;\n", p.Name, p.Type)
+		}
+		b.WriteString("}\n")
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "export class %s extends Chart {\n", c.class)
+	if len(c.params) > 0 {
+		// the props default to the values they were lifted from
+		fmt.Fprintf(&b, indent(1)+"constructor(scope: Construct, id: string, overrides: %sProps = {}) {\n", c.class)
+		b.WriteString(indent(2) + "super(scope, id);\n")
+		b.WriteString("\n")
+		fmt.Fprintf(&b, indent(2)+"const props: Required<%sProps> = {\n", c.class)
+		for _, p := range c.params {
+			fmt.Fprintf(&b, indent(3)+"%s: %s,\n", p.Name, strings.TrimLeft(indentCode(p.Default, 3), " "))
+		}
+		b.WriteString(indent(3) + "...overrides,\n")
+		b.WriteString(indent(2) + "};\n")
+	} else {
+		b.WriteString(indent(1) + "constructor(scope: Construct, id: string) {\n")
+		b.WriteString(indent(2) + "super(scope, id);\n")
+	}
+	if c.body != "" {
+		b.WriteString("\n" + indentCode(c.body, 2))
+	}
+	if len(c.unread) > 0 {
+		// noUnusedLocals rejects constants that are declared and not read
+		b.WriteString("\n")
+		for _, v := range c.unread {
+			fmt.Fprintf(&b, indent(2)+"void %s;\n", v)
+		}
+	}
+	b.WriteString(indent(1) + "}\n")
+	b.WriteString("}\n")
+	b.WriteString("\n")
+	b.WriteString("const app = new App();\n")
+	fmt.Fprintf(&b, "new %s(app, %s);\n", c.class, quote(c.id))
+	b.WriteString("app.synth();\n")
+
+	return b.String()
+}
+
+func pyChart(c chartFile) string {
+	var b strings.Builder
+
+	b.WriteString("#!/usr/bin/env python\n")
+	b.WriteString("from constructs import Construct\n")
+	fmt.Fprintf(&b, "from cdk8s import %s\n", strings.Join(c.cdk8sImports(), ", "))
+	if len(c.modules) > 0 {
+		b.WriteString("\n")
+	}
+	for _, m := range c.modules {
+		b.WriteString(pyImport(m.Path, m.Alias) + "\n")
+	}
+	b.WriteString("\n")
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "class %s(Chart):\n", c.class)
+	b.WriteString(indent(1) + "def __init__(self, scope: Construct, id: str):\n")
+	b.WriteString(indent(2) + "super().__init__(scope, id)\n")
+	if c.body != "" {
+		b.WriteString("\n" + indentCode(c.body, 2))
+	}
+	b.WriteString("\n")
+	b.WriteString("\n")
+	b.WriteString("app = App()\n")
+	fmt.Fprintf(&b, "%s(app, %s)\n", c.class, quote(c.id))
+	b.WriteString("app.synth()\n")
+
+	return b.String()
+}
+
+// pyImport returns the statement importing the Python module modulePath as
+// alias.
+func pyImport(modulePath, alias string) string {
+	stmt := "import " + modulePath
+	name := modulePath
+	if i := strings.LastIndex(modulePath, "."); i >= 0 {
+		stmt = "from " + modulePath[:i] + " import " + modulePath[i+1:]
+		name = modulePath[i+1:]
+	}
+
+	if alias != name {
+		stmt += " as " + alias
+	}
+
+	return stmt
+}
+
+func goChart(c chartFile) string {
+	var b strings.Builder
+
+	b.WriteString("package main\n")
+	b.WriteString("\n")
+	b.WriteString("import (\n")
+	b.WriteString("\"github.com/aws/constructs-go/constructs/v10\"\n")
+	b.WriteString("\"github.com/aws/jsii-runtime-go\"\n")
+	b.WriteString("\"github.com/cdk8s-team/cdk8s-core-go/cdk8s/v2\"\n")
+	if len(c.modules) > 0 {
+		b.WriteString("\n")
+	}
+	for _, m := range c.modules {
+		if m.Alias != path.Base(m.Path) {
+			b.WriteString(m.Alias + " ")
+		}
+		b.WriteString(strconv.Quote(m.Path) + "\n")
+	}
+	b.WriteString(")\n")
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "func New%s(scope constructs.Construct, id string) cdk8s.Chart {\n", c.class)
+	b.WriteString("chart := cdk8s.NewChart(scope, jsii.String(id), nil)\n")
+	if c.body != "" {
+		b.WriteString("\n" + c.body + "\n")
+	}
+	b.WriteString("return chart\n")
+	b.WriteString("}\n")
+	b.WriteString("\n")
+	b.WriteString("func main() {\n")
+	b.WriteString("app := cdk8s.NewApp(nil)\n")
+	fmt.Fprintf(&b, "New%s(app, %s)\n", c.class, quote(c.id))
+	b.WriteString("app.Synth()\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+func javaChart(c chartFile) string {
+	var b strings.Builder
+
+	b.WriteString("package com.mycompany.app;\n")
+	b.WriteString("\n")
+	b.WriteString("import software.constructs.Construct;\n")
+	b.WriteString("\n")
+	for _, name := range c.cdk8sImports() {
+		fmt.Fprintf(&b, "import org.cdk8s.%s;\n", name)
+	}
+	b.WriteString("\n")
+	for _, m := range c.modules {
+		fmt.Fprintf(&b, "import %s.*;\n", m.Path)
+	}
+	if len(c.modules) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString("import java.util.List;\n")
+	b.WriteString("import java.util.Map;\n")
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "public class %s extends Chart {\n", c.class)
+	b.WriteString("\n")
+	fmt.Fprintf(&b, indent(1)+"public %s(final Construct scope, final String id) {\n", c.class)
+	b.WriteString(indent(2) + "super(scope, id);\n")
+	if c.body != "" {
+		b.WriteString("\n" + indentCode(c.body, 2))
+	}
+	b.WriteString(indent(1) + "}\n")
+	b.WriteString("\n")
+	b.WriteString(indent(1) + "public static void main(String[] args) {\n")
+	b.WriteString(indent(2) + "final App app = new App();\n")
+	fmt.Fprintf(&b, indent(2)+"new %s(app, %s);\n", c.class, quote(c.id))
+	b.WriteString(indent(2) + "app.synth();\n")
+	b.WriteString(indent(1) + "}\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+func csChart(c chartFile) string {
+	var b strings.Builder
+
+	b.WriteString("using System.Collections.Generic;\n")
+	b.WriteString("using Constructs;\n")
+	b.WriteString("using Org.Cdk8s;\n")
+	for _, m := range c.modules {
+		fmt.Fprintf(&b, "using %s;\n", m.Path)
+	}
+	b.WriteString("\n")
+	b.WriteString("namespace MyApp\n")
+	b.WriteString("{\n")
+	fmt.Fprintf(&b, indent(1)+"public class %s : Chart\n", c.class)
+	b.WriteString(indent(1) + "{\n")
+	fmt.Fprintf(&b, indent(2)+"public %s(Construct scope, string id) : base(scope, id)\n", c.class)
+	b.WriteString(indent(2) + "{\n")
+	if c.body != "" {
+		b.WriteString(indentCode(c.body, 3))
+	}
+	b.WriteString(indent(2) + "}\n")
+	b.WriteString(indent(1) + "}\n")
+	b.WriteString("\n")
+	b.WriteString(indent(1) + "class Program\n")
+	b.WriteString(indent(1) + "{\n")
+	b.WriteString(indent(2) + "static void Main(string[] args)\n")
+	b.WriteString(indent(2) + "{\n")
+	b.WriteString(indent(3) + "var app = new App();\n")
+	fmt.Fprintf(&b, indent(3)+"new %s(app, %s);\n", c.class, quote(c.id))
+	b.WriteString(indent(3) + "app.Synth();\n")
+	b.WriteString(indent(2) + "}\n")
+	b.WriteString(indent(1) + "}\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
@@ -0,0 +1,141 @@
+package kube2cdk8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bradleyjkemp/cupaloy"
+)
+
+const chartManifest = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-config
+data:
+  script: |
+    echo "` + "`date`" + `"
+    echo done
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: my-service-account
+`
+
+func TestChart(t *testing.T) {
+	for _, language := range []Language{TypeScript, Python, Go, Java, CSharp} {
+		opts := Options{Language: language}
+
+		constructs, err := Convert(context.Background(), strings.NewReader(chartManifest), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		chart, err := Chart("web-app", constructs, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = cupaloy.SnapshotMulti(string(language), chart)
+		if err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
+
+func TestChartClass(t *testing.T) {
+	tests := map[string]string{
+		"web-app":    "WebAppChart",
+		"my-chart":   "MyChart",
+		"webApp":     "WebAppChart",
+		"api_v2":     "ApiV2Chart",
+		"BackendApp": "BackendAppChart",
+	}
+
+	for name, want := range tests {
+		got, err := chartClass(name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s: got %s, want %s", name, got, want)
+		}
+	}
+
+	for _, name := range []string{"", "--", "2048-game"} {
+		if _, err := chartClass(name); err == nil {
+			t.Errorf("%q: expected an error", name)
+		}
+	}
+}
+
+func TestJoinLabelsSources(t *testing.T) {
+	constructs := []Construct{
+		{Code: "a\n", Source: "a.yaml"},
+		{Code: "b\n", Source: "a.yaml"},
+		{Code: "c\n", Source: "c.yaml"},
+	}
+
+	want := "// a.yaml\na\n\nb\n\n// c.yaml\nc\n"
+	if got := Join(constructs, TypeScript); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	want = "a\n\nb\n"
+	if got := Join(constructs[:2], TypeScript); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestChartImport(t *testing.T) {
+	tests := []struct {
+		language Language
+		path     string
+		want     []string
+	}{
+		{TypeScript, "../../imports/k8s-1.22", []string{"import * as kube from '../../imports/k8s-1.22';", "new kube.KubeConfigMap("}},
+		{Python, "imports.k8s", []string{"from imports import k8s as kube\n", "kube.KubeConfigMap(", "kube.ObjectMeta("}},
+		{Python, "k8s_1_22", []string{"import k8s_1_22 as kube\n"}},
+		{Go, "example.com/app/imports/k8s", []string{"kube \"example.com/app/imports/k8s\"", "kube.NewKubeConfigMap(", "&kube.ObjectMeta{"}},
+		{Go, "example.com/app/imports/kube", []string{"\t\"example.com/app/imports/kube\"\n"}},
+		{Java, "com.example.imports.k8s", []string{"import com.example.imports.k8s.*;", "new KubeConfigMap("}},
+		{CSharp, "Example.Imports.K8s", []string{"using Example.Imports.K8s;", "new KubeConfigMap("}},
+	}
+
+	for _, tt := range tests {
+		opts := Options{Language: tt.language, ImportAlias: "kube", ImportPath: tt.path}
+
+		constructs, err := Convert(context.Background(), strings.NewReader(chartManifest), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		chart, err := Chart("", constructs, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, want := range tt.want {
+			if !strings.Contains(chart, want) {
+				t.Errorf("%s: expected %q in\n%s", tt.language, want, chart)
+			}
+		}
+		if strings.Contains(chart, "k8s.Kube") || strings.Contains(chart, "k8s.ObjectMeta") {
+			t.Errorf("%s: unexpected k8s. prefix in\n%s", tt.language, chart)
+		}
+	}
+}
+
+func TestInvalidImportAlias(t *testing.T) {
+	opts := Options{ImportAlias: "my-kube"}
+
+	if _, err := Convert(context.Background(), strings.NewReader(chartManifest), opts); err == nil {
+		t.Error("Convert: expected an error")
+	}
+	if _, err := Chart("", nil, opts); err == nil {
+		t.Error("Chart: expected an error")
+	}
+}
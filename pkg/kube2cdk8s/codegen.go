@@ -0,0 +1,90 @@
+package kube2cdk8s
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// writeList writes items as a bracketed list. A single item is kept on the
+// opening line, e.g. `[{`, while longer lists and commented items put every
+// item on its own line after its comments.
+func writeList(b *strings.Builder, language Language, items []*node, level int, item func(n *node, level int)) {
+	switch {
+	case len(items) == 0:
+		b.WriteString("[]")
+	case len(items) == 1 && len(items[0].comments) == 0:
+		b.WriteString("[")
+		item(items[0], level)
+		b.WriteString("]")
+	default:
+		b.WriteString("[")
+		for _, n := range items {
+			writeComments(b, language, n.comments, level+1)
+			b.WriteString("\n" + indent(level+1))
+			item(n, level+1)
+			b.WriteString(",")
+		}
+		b.WriteString("\n" + indent(level) + "]")
+	}
+}
+
+// apiObjectProps splits the object of r into the props of a plain ApiObject,
+// its apiVersion, kind and metadata, and its other top-level fields, which
+// cdk8s only takes as JSON patches outside TypeScript.
+func apiObjectProps(r *resource) (*node, []*field) {
+	props := &node{kind: mapNode, fields: []*field{
+		{key: "apiVersion", value: &node{kind: stringNode, value: r.apiVersion}},
+		{key: "kind", value: &node{kind: stringNode, value: r.kind}},
+	}}
+
+	var rest []*field
+	for _, f := range r.props().fields {
+		if f.key == "metadata" {
+			props.fields = append(props.fields, f)
+		} else {
+			rest = append(rest, f)
+		}
+	}
+
+	return props, rest
+}
+
+// jsonPointer returns the JSON pointer of the top-level field key.
+func jsonPointer(key string) string {
+	return "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// writeComments writes comments as line comments of language, each on its
+// own line indented at level.
+func writeComments(b *strings.Builder, language Language, comments []string, level int) {
+	for _, c := range comments {
+		b.WriteString("\n" + indent(level) + strings.TrimRight(language.Comment(c), " "))
+	}
+}
+
+// commentBlock returns comments as line comments of language, each on its
+// own line, to write above a construct.
+func commentBlock(language Language, comments []string) string {
+	var b strings.Builder
+	for _, c := range comments {
+		b.WriteString(strings.TrimRight(language.Comment(c), " ") + "\n")
+	}
+
+	return b.String()
+}
+
+// quote returns s as a double-quoted string literal.
+func quote(s string) string {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func indent(level int) string {
+	return strings.Repeat("    ", level)
+}
@@ -0,0 +1,225 @@
+package kube2cdk8s
+
+import (
+	"fmt"
+	"strings"
+)
+
+// csharp renders r as a cdk8s C# construct using object initializers.
+func csharp(r *resource) string {
+	var b strings.Builder
+	s := r.scope
+
+	b.WriteString(commentBlock(CSharp, r.comments))
+
+	props := r.props()
+	var patches []*field
+	if s.apiObject {
+		props, patches = apiObjectProps(r)
+	}
+
+	if r.declare {
+		fmt.Fprintf(&b, "var %s = ", r.variable)
+	}
+	fmt.Fprintf(&b, "new %s(this, %s, ", s.className(), quote(r.id))
+	csObject(&b, s, s.propsType(), props, 0)
+	b.WriteString(")")
+
+	if len(patches) > 0 {
+		if r.declare {
+			// AddJsonPatch returns nothing to assign
+			b.WriteString(";\n" + r.variable)
+		}
+		b.WriteString(".AddJsonPatch(")
+		for i, f := range patches {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			writeComments(&b, CSharp, f.value.comments, 1)
+			b.WriteString("\n" + indent(1) + "JsonPatch.Add(" + quote(jsonPointer(f.key)) + ", ")
+			csLiteral(&b, f.value, 1)
+			b.WriteString(")")
+		}
+		b.WriteString(")")
+	}
+	b.WriteString(";\n")
+
+	return b.String()
+}
+
+// csObject writes n as an object initializer of struct name declared in
+// scope s.
+func csObject(b *strings.Builder, s *scope, name string, n *node, level int) {
+	b.WriteString("new " + name + " {")
+	if len(n.fields) == 0 {
+		b.WriteString(" }")
+		return
+	}
+
+	for _, f := range n.fields {
+		writeComments(b, CSharp, f.value.comments, level+1)
+		b.WriteString("\n" + indent(level+1) + upperFirst(jsiiName(f.key)) + " = ")
+		csValue(b, s, s.fieldType(name, f.key, f.value), f.value, level+1)
+		b.WriteString(",")
+	}
+	b.WriteString("\n" + indent(level) + "}")
+}
+
+// csValue writes n as a C# expression of type t indented at level.
+func csValue(b *strings.Builder, s *scope, t typeRef, n *node, level int) {
+	switch {
+	case t.kind == structType && n.kind == mapNode:
+		csObject(b, s, t.name, n, level)
+
+	case t.kind == listType && n.kind == listNode:
+		csArray(b, csType(*t.elem, n.items), n.items, level, func(item *node, level int) {
+			csValue(b, s, *t.elem, item, level)
+		})
+
+	case t.kind == mapType && n.kind == mapNode:
+		elem := "string"
+		if t.elem.kind != inferredType {
+			elem = csType(*t.elem, nil)
+		}
+		csDictionary(b, elem, n, level, func(item *node, level int) {
+			if t.elem.kind == inferredType {
+				b.WriteString(quote(item.value))
+			} else {
+				csValue(b, s, *t.elem, item, level)
+			}
+		})
+
+	case t.kind == quantityType:
+		csUnion(b, "Quantity", n)
+
+	case t.kind == intOrStringType:
+		csUnion(b, "IntOrString", n)
+
+	case t.kind == jsonType:
+		csLiteral(b, n, level)
+
+	default:
+		if n.kind == listNode {
+			csArray(b, csType(t, n.items), n.items, level, func(item *node, level int) {
+				csLiteral(b, item, level)
+			})
+			return
+		}
+		csLiteral(b, n, level)
+	}
+}
+
+// csType returns the C# element type of an array or dictionary of t. items
+// are the values of an array of scalars, whose element type comes from the
+// values.
+func csType(t typeRef, items []*node) string {
+	switch t.kind {
+	case structType:
+		return t.name
+	case quantityType:
+		return "Quantity"
+	case intOrStringType:
+		return "IntOrString"
+	case jsonType:
+		return "object"
+	}
+
+	kind := nullNode
+	for _, item := range items {
+		if kind != nullNode && kind != item.kind {
+			return "object"
+		}
+		kind = item.kind
+	}
+
+	switch kind {
+	case stringNode:
+		return "string"
+	case intNode, floatNode:
+		return "double"
+	case boolNode:
+		return "bool"
+	}
+
+	return "string"
+}
+
+// csUnion writes n through the FromNumber or FromString factory of a cdk8s
+// union class such as Quantity.
+func csUnion(b *strings.Builder, class string, n *node) {
+	switch n.kind {
+	case intNode, floatNode:
+		fmt.Fprintf(b, "%s.FromNumber(%s)", class, n.value)
+	default:
+		fmt.Fprintf(b, "%s.FromString(%s)", class, quote(n.value))
+	}
+}
+
+// csLiteral writes n as an untyped C# value for properties that take
+// arbitrary data.
+func csLiteral(b *strings.Builder, n *node, level int) {
+	switch n.kind {
+	case nullNode:
+		b.WriteString("null")
+	case stringNode:
+		b.WriteString(quote(n.value))
+	case mapNode:
+		csDictionary(b, "object", n, level, func(item *node, level int) {
+			csLiteral(b, item, level)
+		})
+	case listNode:
+		csArray(b, "object", n.items, level, func(item *node, level int) {
+			csLiteral(b, item, level)
+		})
+	default:
+		b.WriteString(n.value)
+	}
+}
+
+// csArray writes items as an array of elem. The element type is spelled out
+// only where C# cannot infer it.
+func csArray(b *strings.Builder, elem string, items []*node, level int, item func(n *node, level int)) {
+	switch {
+	case len(items) == 0:
+		b.WriteString("new " + elem + "[] {}")
+	case len(items) == 1 && len(items[0].comments) == 0:
+		b.WriteString(csNewArray(elem) + " { ")
+		item(items[0], level)
+		b.WriteString(" }")
+	default:
+		b.WriteString(csNewArray(elem) + " {")
+		for _, n := range items {
+			writeComments(b, CSharp, n.comments, level+1)
+			b.WriteString("\n" + indent(level+1))
+			item(n, level+1)
+			b.WriteString(",")
+		}
+		b.WriteString("\n" + indent(level) + "}")
+	}
+}
+
+func csNewArray(elem string) string {
+	switch elem {
+	case "object", "double":
+		return "new " + elem + "[]"
+	}
+
+	return "new []"
+}
+
+// csDictionary writes n as a Dictionary<string, elem> collection initializer.
+func csDictionary(b *strings.Builder, elem string, n *node, level int, value func(n *node, level int)) {
+	b.WriteString("new Dictionary<string, " + elem + "> {")
+	if len(n.fields) == 0 {
+		b.WriteString(" }")
+		return
+	}
+
+	for _, f := range n.fields {
+		writeComments(b, CSharp, f.value.comments, level+1)
+		b.WriteString("\n" + indent(level+1) + "{ " + quote(f.key) + ", ")
+		value(f.value, level+1)
+		b.WriteString(" },")
+	}
+	b.WriteString("\n" + indent(level) + "}")
+}
@@ -0,0 +1,212 @@
+package kube2cdk8s
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// manifestExtensions are the extensions of the files converted when a
+// directory is given.
+var manifestExtensions = map[string]bool{
+	".yaml": true,
+	".yml":  true,
+	".json": true,
+}
+
+// FindFiles expands paths into the manifest files to convert. A path can be
+// a file, a directory, whose .yaml, .yml and .json files are used, or a glob
+// where ** matches any number of directories. Directories are only descended
+// into when recursive is set. The files are returned sorted and without
+// duplicates.
+func FindFiles(paths []string, recursive bool) ([]string, error) {
+	seen := map[string]bool{}
+	var files []string
+
+	add := func(f string) {
+		if !seen[f] {
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+
+	for _, p := range paths {
+		matches := []string{p}
+		if isGlob(p) {
+			var err error
+			matches, err = glob(p)
+			if err != nil {
+				return nil, err
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no files match %s", p)
+			}
+		}
+
+		for _, m := range matches {
+			info, err := os.Stat(m)
+			if err != nil {
+				return nil, err
+			}
+
+			if !info.IsDir() {
+				add(m)
+				continue
+			}
+
+			found, err := dirFiles(m, recursive)
+			if err != nil {
+				return nil, err
+			}
+			if len(found) == 0 {
+				return nil, fmt.Errorf("unable to find any YAML files in directory: %s", m)
+			}
+			for _, f := range found {
+				add(f)
+			}
+		}
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// ConvertFiles converts every document of files to cdk8s constructs, see
+// Convert. Each construct records the file it was read from.
+func ConvertFiles(ctx context.Context, files []string, opts Options) ([]Construct, error) {
+	var docs []document
+	var errs Errors
+
+	for _, f := range files {
+		input, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		fileDocs, decodeErr := decodeSource(f, input)
+		docs = append(docs, fileDocs...)
+		if decodeErr != nil {
+			errs = append(errs, decodeErr)
+		}
+	}
+
+	return convertDocuments(ctx, docs, errs, opts)
+}
+
+// dirFiles returns the manifest files in dir.
+func dirFiles(dir string, recursive bool) ([]string, error) {
+	var files []string
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if p != dir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if manifestExtensions[strings.ToLower(filepath.Ext(p))] {
+			files = append(files, p)
+		}
+		return nil
+	})
+
+	return files, err
+}
+
+func isGlob(p string) bool {
+	return strings.ContainsAny(p, "*?[")
+}
+
+// glob returns the paths matching pattern. Unlike filepath.Glob, ** matches
+// any number of directories.
+func glob(pattern string) ([]string, error) {
+	if !strings.Contains(pattern, "**") {
+		return filepath.Glob(pattern)
+	}
+
+	pattern = path.Clean(filepath.ToSlash(pattern))
+	re, err := globRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	// walk from the longest leading directory without wildcards
+	root := "."
+	segments := strings.Split(pattern, "/")
+	for i, s := range segments {
+		if isGlob(s) {
+			if i > 0 {
+				root = strings.Join(segments[:i], "/")
+				if root == "" {
+					root = "/"
+				}
+			}
+			break
+		}
+	}
+
+	var matches []string
+	err = filepath.WalkDir(filepath.FromSlash(root), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() && re.MatchString(filepath.ToSlash(p)) {
+			matches = append(matches, p)
+		}
+		return nil
+	})
+
+	return matches, err
+}
+
+// globRegexp translates a slash-separated glob pattern to a regular
+// expression.
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	var re strings.Builder
+
+	re.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case c == '*':
+			re.WriteString("[^/]*")
+		case c == '?':
+			re.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%s: %w", pattern, filepath.ErrBadPattern)
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			re.WriteString("[" + class + "]")
+			i += end
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	re.WriteString("$")
+
+	return regexp.Compile(re.String())
+}
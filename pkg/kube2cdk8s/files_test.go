@@ -0,0 +1,139 @@
+package kube2cdk8s
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// manifestTree creates a directory of manifests and returns its path.
+func manifestTree(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"ns.json":         `{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "ns"}}`,
+		"README.md":       "not a manifest",
+		"api/sa.yaml":     "apiVersion: v1\nkind: ServiceAccount\nmetadata:\n  name: api\n",
+		"api/sub/cm.yml":  "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: deep\n",
+		"web/all.yml":     "apiVersion: v1\nkind: ServiceAccount\nmetadata:\n  name: web\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\n",
+		"web/ignored.txt": "apiVersion: v1\nkind: ConfigMap\n",
+	}
+	for name, text := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestFindFiles(t *testing.T) {
+	dir := manifestTree(t)
+	path := func(name string) string {
+		return filepath.Join(dir, filepath.FromSlash(name))
+	}
+
+	tests := []struct {
+		name      string
+		paths     []string
+		recursive bool
+		want      []string
+	}{
+		{"directory", []string{dir}, false, []string{path("ns.json")}},
+		{"recursive", []string{dir}, true, []string{path("api/sa.yaml"), path("api/sub/cm.yml"), path("ns.json"), path("web/all.yml")}},
+		{"glob", []string{path("*/*.yaml")}, false, []string{path("api/sa.yaml")}},
+		{"double star", []string{path("**/*.yml")}, false, []string{path("api/sub/cm.yml"), path("web/all.yml")}},
+		{"repeated", []string{path("web/all.yml"), path("api"), path("web/all.yml")}, false, []string{path("api/sa.yaml"), path("web/all.yml")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindFiles(tt.paths, tt.recursive)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindFilesErrors(t *testing.T) {
+	dir := manifestTree(t)
+
+	for _, paths := range [][]string{
+		{filepath.Join(dir, "*.yaml")},
+		{filepath.Join(dir, "missing.yaml")},
+	} {
+		if _, err := FindFiles(paths, false); err == nil {
+			t.Errorf("%v: expected an error", paths)
+		}
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FindFiles([]string{filepath.Join(dir, "empty")}, true); err == nil {
+		t.Error("empty directory: expected an error")
+	}
+}
+
+func TestConvertFiles(t *testing.T) {
+	dir := manifestTree(t)
+
+	files, err := FindFiles([]string{dir}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	constructs, err := ConvertFiles(context.Background(), files, Options{Jobs: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, c := range constructs {
+		rel, err := filepath.Rel(dir, c.Source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, filepath.ToSlash(rel)+" "+c.Kind+"/"+c.Name)
+	}
+
+	want := []string{
+		"api/sa.yaml ServiceAccount/api",
+		"api/sub/cm.yml ConfigMap/deep",
+		"ns.json Namespace/ns",
+		"web/all.yml ServiceAccount/web",
+		"web/all.yml ConfigMap/web",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestConvertFilesErrors(t *testing.T) {
+	dir := manifestTree(t)
+
+	bad := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(bad, []byte("apiVersion: v1\nmetadata:\n  name: bad\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := ConvertFiles(context.Background(), []string{bad, filepath.Join(dir, "ns.json")}, Options{})
+	errs, ok := err.(Errors)
+	if !ok || len(errs) != 1 {
+		t.Fatalf("expected one document error, got %v", err)
+	}
+	if errs[0].Source != bad || errs[0].Document != 1 {
+		t.Errorf("unexpected error %v", errs[0])
+	}
+}
@@ -0,0 +1,255 @@
+package kube2cdk8s
+
+import (
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+)
+
+// golang renders r as a cdk8s Go construct, formatted with gofmt.
+func golang(r *resource) string {
+	var b strings.Builder
+	s := r.scope
+
+	b.WriteString(commentBlock(Go, r.comments))
+
+	props := r.props()
+	var patches []*field
+	if s.apiObject {
+		props, patches = apiObjectProps(r)
+	}
+
+	if r.declare {
+		b.WriteString(r.variable + " := ")
+	}
+	fmt.Fprintf(&b, "%s.New%s(chart, jsii.String(%s), &%s.%s", s.module, s.className(), strconv.Quote(r.id), s.module, s.propsType())
+	goStruct(&b, s, s.propsType(), props)
+	b.WriteString(")")
+
+	if len(patches) > 0 {
+		if r.declare {
+			// AddJsonPatch returns nothing to assign
+			b.WriteString("\n" + r.variable)
+		}
+		b.WriteString(".AddJsonPatch(")
+		for _, f := range patches {
+			writeComments(&b, Go, f.value.comments, 0)
+			b.WriteString("\ncdk8s.JsonPatch_Add(jsii.String(" + strconv.Quote(jsonPointer(f.key)) + "), ")
+			goLiteral(&b, f.value)
+			b.WriteString("),")
+		}
+		b.WriteString("\n)")
+	} else if r.declare {
+		// Go rejects variables that are declared and not used
+		b.WriteString("\n_ = " + r.variable)
+	}
+	b.WriteString("\n")
+
+	formatted, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return b.String()
+	}
+
+	return string(formatted)
+}
+
+// goStruct writes the body of a composite literal of struct name declared in
+// scope s.
+func goStruct(b *strings.Builder, s *scope, name string, n *node) {
+	b.WriteString("{")
+	for _, f := range n.fields {
+		writeComments(b, Go, f.value.comments, 0)
+		b.WriteString("\n" + upperFirst(jsiiName(f.key)) + ": ")
+		goValue(b, s, s.fieldType(name, f.key, f.value), f.value)
+		b.WriteString(",")
+	}
+	if len(n.fields) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString("}")
+}
+
+// goValue writes n as a Go expression of type t declared in scope s.
+func goValue(b *strings.Builder, s *scope, t typeRef, n *node) {
+	if n.kind == nullNode {
+		b.WriteString("nil")
+		return
+	}
+
+	switch {
+	case t.kind == structType && n.kind == mapNode:
+		b.WriteString("&" + s.moduleOf(t) + "." + t.name)
+		goStruct(b, s, t.name, n)
+
+	case t.kind == listType && n.kind == listNode:
+		b.WriteString("&[]" + goType(s, *t.elem, n.items) + "{")
+		for _, item := range n.items {
+			writeComments(b, Go, item.comments, 0)
+			b.WriteString("\n")
+			if t.elem.kind == structType && item.kind == mapNode {
+				// the element type is elided, {...} is a *k8s.Name here
+				goStruct(b, s, t.elem.name, item)
+			} else {
+				goValue(b, s, *t.elem, item)
+			}
+			b.WriteString(",")
+		}
+		if len(n.items) > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("}")
+
+	case t.kind == mapType && n.kind == mapNode:
+		// free-form maps such as labels are map[string]*string
+		elem := "*string"
+		if t.elem.kind != inferredType {
+			elem = goType(s, *t.elem, nil)
+		}
+		b.WriteString("&map[string]" + elem + "{")
+		for _, f := range n.fields {
+			writeComments(b, Go, f.value.comments, 0)
+			b.WriteString("\n" + strconv.Quote(f.key) + ": ")
+			if t.elem.kind == inferredType {
+				b.WriteString("jsii.String(" + strconv.Quote(f.value.value) + ")")
+			} else {
+				goValue(b, s, *t.elem, f.value)
+			}
+			b.WriteString(",")
+		}
+		if len(n.fields) > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("}")
+
+	case t.kind == quantityType:
+		goUnion(b, s.module, "Quantity", n)
+
+	case t.kind == intOrStringType:
+		goUnion(b, s.module, "IntOrString", n)
+
+	case t.kind == jsonType:
+		if n.kind == mapNode {
+			b.WriteString("&")
+		}
+		goLiteral(b, n)
+
+	default:
+		goScalar(b, n)
+	}
+}
+
+// goType returns the Go element type of a list or map of t. items are the
+// values of a list of scalars, whose element type comes from the values.
+func goType(s *scope, t typeRef, items []*node) string {
+	switch t.kind {
+	case structType:
+		return "*" + s.moduleOf(t) + "." + t.name
+	case quantityType:
+		return s.module + ".Quantity"
+	case intOrStringType:
+		return s.module + ".IntOrString"
+	case jsonType:
+		return "interface{}"
+	}
+
+	kind := nullNode
+	for _, item := range items {
+		if kind != nullNode && kind != item.kind {
+			return "interface{}"
+		}
+		kind = item.kind
+	}
+
+	switch kind {
+	case stringNode:
+		return "*string"
+	case intNode, floatNode:
+		return "*float64"
+	case boolNode:
+		return "*bool"
+	}
+
+	return "interface{}"
+}
+
+// goUnion writes n through the FromNumber or FromString factory of a cdk8s
+// union class such as Quantity.
+func goUnion(b *strings.Builder, module, class string, n *node) {
+	switch n.kind {
+	case intNode, floatNode:
+		fmt.Fprintf(b, "%s.%s_FromNumber(jsii.Number(%s))", module, class, n.value)
+	default:
+		fmt.Fprintf(b, "%s.%s_FromString(jsii.String(%s))", module, class, strconv.Quote(n.value))
+	}
+}
+
+// goScalar writes a scalar with the jsii pointer helper for its type.
+func goScalar(b *strings.Builder, n *node) {
+	switch n.kind {
+	case stringNode:
+		b.WriteString("jsii.String(" + strconv.Quote(n.value) + ")")
+	case intNode, floatNode:
+		b.WriteString("jsii.Number(" + n.value + ")")
+	case boolNode:
+		b.WriteString("jsii.Bool(" + n.value + ")")
+	case listNode:
+		elem := goType(nil, typeRef{kind: inferredType}, n.items)
+		if elem == "interface{}" {
+			goLiteral(b, n)
+			return
+		}
+		b.WriteString("&[]" + elem + "{")
+		for _, item := range n.items {
+			writeComments(b, Go, item.comments, 0)
+			b.WriteString("\n")
+			goScalar(b, item)
+			b.WriteString(",")
+		}
+		if len(n.items) > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("}")
+	case mapNode:
+		goLiteral(b, n)
+	default:
+		b.WriteString("nil")
+	}
+}
+
+// goLiteral writes n as an untyped Go value for properties that take
+// arbitrary data.
+func goLiteral(b *strings.Builder, n *node) {
+	switch n.kind {
+	case stringNode:
+		b.WriteString(strconv.Quote(n.value))
+	case mapNode:
+		b.WriteString("map[string]interface{}{")
+		for _, f := range n.fields {
+			writeComments(b, Go, f.value.comments, 0)
+			b.WriteString("\n" + strconv.Quote(f.key) + ": ")
+			goLiteral(b, f.value)
+			b.WriteString(",")
+		}
+		if len(n.fields) > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("}")
+	case listNode:
+		b.WriteString("[]interface{}{")
+		for _, item := range n.items {
+			writeComments(b, Go, item.comments, 0)
+			b.WriteString("\n")
+			goLiteral(b, item)
+			b.WriteString(",")
+		}
+		if len(n.items) > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("}")
+	case nullNode:
+		b.WriteString("nil")
+	default:
+		b.WriteString(n.value)
+	}
+}
@@ -0,0 +1,97 @@
+package kube2cdk8s
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	"github.com/smallcase/kube2cdk8s/util"
+
+	"github.com/bradleyjkemp/cupaloy"
+)
+
+func TestKube2CDK8SGoDeployment(t *testing.T) {
+
+	deployment := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-deployment
+  namespace: my-namespace
+spec:
+  selector:
+    matchLabels:
+      app: my-deployment
+  replicas: 3
+  template:
+    metadata:
+      labels:
+        app: my-deployment
+    spec:
+      containers:
+      - name: my-deployment
+        image: my-image
+        imagePullPolicy: Always
+        args: ["--port", "8080"]
+        ports:
+        - containerPort: 8080
+        resources:
+          limits:
+            cpu: 500m
+            memory: 1Gi
+          requests:
+            cpu: 1
+        readinessProbe:
+          httpGet:
+            path: /healthz
+            port: http
+`
+	deploymentFile, err := util.CreateTempFile([]byte(deployment))
+	if err != nil {
+		log.Println(err.Error())
+	}
+
+	d, err := Kube2CDK8SLanguage(deploymentFile.Name(), Go)
+	if err != nil {
+		log.Println(err.Error())
+	}
+
+	err = cupaloy.Snapshot(d)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	defer os.Remove(deploymentFile.Name())
+}
+
+func TestKube2CDK8SGoRole(t *testing.T) {
+
+	role := `apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: argocd-application-controller
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: argocd-application-controller
+subjects:
+- kind: ServiceAccount
+  name: argocd-application-controller
+`
+	roleFile, err := util.CreateTempFile([]byte(role))
+	if err != nil {
+		log.Println(err.Error())
+	}
+
+	d, err := Kube2CDK8SLanguage(roleFile.Name(), Go)
+	if err != nil {
+		log.Println(err.Error())
+	}
+
+	err = cupaloy.Snapshot(d)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	defer os.Remove(roleFile.Name())
+}
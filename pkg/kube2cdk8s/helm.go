@@ -0,0 +1,127 @@
+package kube2cdk8s
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+)
+
+// DefaultReleaseName is the release name Helm charts are rendered with when
+// none is given, the one helm template uses.
+const DefaultReleaseName = "release-name"
+
+// HelmChart is a Helm chart on disk and the values it is rendered with.
+type HelmChart struct {
+	// Path is the directory or packaged archive of the chart.
+	Path string
+
+	// ReleaseName is the name of the release, DefaultReleaseName if empty.
+	ReleaseName string
+
+	// ValuesFiles are the values files applied in order, as with helm -f.
+	ValuesFiles []string
+
+	// Set are the key=value overrides applied after the values files, as
+	// with helm --set.
+	Set []string
+
+	// Binary is the helm executable, looked up in PATH if empty.
+	Binary string
+}
+
+// helmSourcePrefix starts the comment helm template writes above each
+// rendered document to name the template it comes from.
+const helmSourcePrefix = "# Source: "
+
+// Render renders the templates of the chart locally with helm template,
+// without contacting a cluster or fetching dependencies.
+func (c HelmChart) Render(ctx context.Context) ([]byte, error) {
+	binary := c.Binary
+	if binary == "" {
+		binary = "helm"
+	}
+
+	releaseName := c.ReleaseName
+	if releaseName == "" {
+		releaseName = DefaultReleaseName
+	}
+
+	args := []string{"template", releaseName, c.Path}
+	for _, f := range c.ValuesFiles {
+		args = append(args, "--values", f)
+	}
+	for _, s := range c.Set {
+		args = append(args, "--set", s)
+	}
+
+	return render(ctx, "helm chart "+c.Path, binary, args)
+}
+
+// ConvertHelmChart renders chart and converts every rendered document to
+// cdk8s constructs, see Convert. Each construct records the template it was
+// rendered from as its Source.
+func ConvertHelmChart(ctx context.Context, chart HelmChart, opts Options) ([]Construct, error) {
+	// fail before running the tool rather than after
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	rendered, err := chart.Render(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []document
+	var errs Errors
+
+	for _, t := range splitTemplates(rendered) {
+		templateDocs, decodeErr := decodeSource(t.source, t.input)
+		docs = append(docs, templateDocs...)
+		if decodeErr != nil {
+			errs = append(errs, decodeErr)
+		}
+	}
+
+	return convertDocuments(ctx, docs, errs, opts)
+}
+
+// renderedTemplate is the output of a single template of a Helm chart.
+type renderedTemplate struct {
+	source string
+	input  []byte
+}
+
+// splitTemplates splits the output of helm template by the template each
+// document was rendered from, dropping the # Source comments. Consecutive
+// documents of the same template are kept together.
+func splitTemplates(rendered []byte) []renderedTemplate {
+	var templates []renderedTemplate
+	current := renderedTemplate{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(rendered))
+	scanner.Buffer(make([]byte, 64*1024), len(rendered)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, helmSourcePrefix) {
+			source := strings.TrimSpace(strings.TrimPrefix(line, helmSourcePrefix))
+			if source != current.source {
+				if len(bytes.TrimSpace(current.input)) > 0 {
+					templates = append(templates, current)
+				}
+				current = renderedTemplate{source: source, input: []byte("---\n")}
+			}
+			continue
+		}
+
+		current.input = append(current.input, line...)
+		current.input = append(current.input, '\n')
+	}
+
+	if len(bytes.TrimSpace(current.input)) > 0 {
+		templates = append(templates, current)
+	}
+
+	return templates
+}
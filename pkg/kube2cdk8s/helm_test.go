@@ -0,0 +1,109 @@
+package kube2cdk8s
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/bradleyjkemp/cupaloy"
+)
+
+const renderedChart = `---
+# Source: api/templates/serviceaccount.yaml
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: prod-api
+---
+# Source: api/templates/config.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: prod-api
+data:
+  level: debug
+---
+# Source: api/templates/config.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: prod-api-extra
+`
+
+// fakeTool writes an executable called name that records its arguments in
+// args and prints rendered.
+func fakeTool(t *testing.T, name, rendered string) (binary, args string) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("fake " + name + " is a shell script")
+	}
+
+	dir := t.TempDir()
+	binary = filepath.Join(dir, name)
+	args = filepath.Join(dir, "args")
+	output := filepath.Join(dir, "output.yaml")
+
+	if err := os.WriteFile(output, []byte(rendered), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	script := "#!/bin/sh\necho \"$@\" > " + args + "\ncat " + output + "\n"
+	if err := os.WriteFile(binary, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	return binary, args
+}
+
+func TestConvertHelmChart(t *testing.T) {
+	binary, args := fakeTool(t, "helm", renderedChart)
+
+	chart := HelmChart{
+		Path:        "./charts/api",
+		ReleaseName: "prod",
+		ValuesFiles: []string{"values-prod.yaml"},
+		Set:         []string{"image.tag=1.2.3"},
+		Binary:      binary,
+	}
+
+	constructs, err := ConvertHelmChart(context.Background(), chart, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "template prod ./charts/api --values values-prod.yaml --set image.tag=1.2.3"
+	if strings.TrimSpace(string(got)) != want {
+		t.Errorf("got helm %s, want helm %s", got, want)
+	}
+
+	var sources []string
+	for _, c := range constructs {
+		sources = append(sources, c.Source)
+	}
+	wantSources := []string{"api/templates/serviceaccount.yaml", "api/templates/config.yaml", "api/templates/config.yaml"}
+	if !reflect.DeepEqual(sources, wantSources) {
+		t.Errorf("got sources %v, want %v", sources, wantSources)
+	}
+
+	err = cupaloy.Snapshot(Join(constructs, TypeScript))
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestHelmChartError(t *testing.T) {
+	chart := HelmChart{Path: "./charts/api", Binary: filepath.Join(t.TempDir(), "missing-helm")}
+
+	if _, err := ConvertHelmChart(context.Background(), chart, Options{}); err == nil {
+		t.Error("expected an error")
+	}
+}
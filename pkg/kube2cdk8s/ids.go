@@ -0,0 +1,132 @@
+package kube2cdk8s
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// The strategies of Options.IDStrategy. Any other value is a text/template
+// rendered with an IDData.
+const (
+	// IDName uses the name of the object, e.g. api.
+	IDName = "name"
+
+	// IDKindName prefixes the name with the kind, e.g. deployment-api.
+	IDKindName = "kind-name"
+
+	// IDNamespaceKindName prefixes the name with the namespace, if any, and
+	// the kind, e.g. prod-deployment-api.
+	IDNamespaceKindName = "namespace-kind-name"
+)
+
+// IDData is what an Options.IDStrategy template is rendered with.
+type IDData struct {
+	APIVersion string
+	Group      string
+	Version    string
+	Kind       string
+	Namespace  string
+
+	// Name is the name of the object or, when it has none, its generateName
+	// without the trailing dash, or else its kind in lower case.
+	Name string
+}
+
+var idFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// idTemplate returns the template of the ID strategy set in opts.
+func (opts Options) idTemplate() (*template.Template, error) {
+	text := opts.IDStrategy
+	switch text {
+	case "", IDName:
+		text = "{{.Name}}"
+	case IDKindName:
+		text = "{{lower .Kind}}-{{.Name}}"
+	case IDNamespaceKindName:
+		text = "{{with .Namespace}}{{.}}-{{end}}{{lower .Kind}}-{{.Name}}"
+	default:
+		if !strings.Contains(text, "{{") {
+			return nil, fmt.Errorf("unknown id strategy %q, expected %s, %s, %s or a template", text, IDName, IDKindName, IDNamespaceKindName)
+		}
+	}
+
+	t, err := template.New("id").Funcs(idFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id template: %w", err)
+	}
+
+	return t, nil
+}
+
+// assignIDs sets the construct ID of every resource with the ID strategy set
+// in opts. When an ID was already given to an earlier resource, the kind is
+// appended to it and then a number, until it is unique.
+//
+// Objects without a name, such as the ones with a generateName, are given
+// one, see IDData.
+func (opts Options) assignIDs(resources []*resource) error {
+	t, err := opts.idTemplate()
+	if err != nil {
+		return err
+	}
+
+	used := map[string]bool{}
+	for _, r := range resources {
+		if r == nil {
+			continue
+		}
+
+		name := r.name
+		if name == "" {
+			name = strings.TrimSuffix(r.object.get("metadata").str("generateName"), "-")
+		}
+		if name == "" {
+			name = strings.ToLower(r.kind)
+		}
+
+		var id bytes.Buffer
+		err := t.Execute(&id, IDData{
+			APIVersion: r.apiVersion,
+			Group:      apiGroup(r.apiVersion),
+			Version:    apiVersionOf(r.apiVersion),
+			Kind:       r.kind,
+			Namespace:  r.object.get("metadata").str("namespace"),
+			Name:       name,
+		})
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", r.kind, name, err)
+		}
+
+		r.id = uniqueID(id.String(), r.kind, used)
+		used[r.id] = true
+	}
+
+	return nil
+}
+
+// uniqueID returns id, or when it is used, id suffixed with the kind and, if
+// still needed, a number.
+func uniqueID(id, kind string, used map[string]bool) string {
+	if !used[id] {
+		return id
+	}
+
+	// the kind only tells objects apart when the ID does not hold it yet
+	suffix := "-" + strings.ToLower(kind)
+	if id == "" || strings.Contains(strings.ToLower(id), strings.ToLower(kind)) {
+		suffix = ""
+	}
+
+	unique := id + suffix
+	for i := 2; used[unique]; i++ {
+		unique = id + suffix + "-" + strconv.Itoa(i)
+	}
+
+	return unique
+}
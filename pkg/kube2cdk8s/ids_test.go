@@ -0,0 +1,102 @@
+package kube2cdk8s
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sharedNames = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: prod
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+  namespace: prod
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: api
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+  namespace: staging
+`
+
+func TestIDStrategies(t *testing.T) {
+	tests := []struct {
+		strategy string
+		want     []string
+	}{
+		{"", []string{"api", "api-service", "api-serviceaccount", "api-service-2"}},
+		{IDKindName, []string{"deployment-api", "service-api", "serviceaccount-api", "service-api-2"}},
+		{IDNamespaceKindName, []string{"prod-deployment-api", "prod-service-api", "serviceaccount-api", "staging-service-api"}},
+		{"{{.Group}}{{.Kind}}.{{upper .Name}}", []string{"appsDeployment.API", "Service.API", "ServiceAccount.API", "Service.API-2"}},
+	}
+
+	for _, tt := range tests {
+		constructs, err := Convert(context.Background(), strings.NewReader(sharedNames), Options{IDStrategy: tt.strategy})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var ids []string
+		for _, c := range constructs {
+			ids = append(ids, c.ID)
+			if !strings.Contains(c.Code, "(this, "+quote(c.ID)+", ") {
+				t.Errorf("%q: %s is not created with id %s:\n%s", tt.strategy, c.Kind, c.ID, c.Code)
+			}
+		}
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("%q: got ids %v, want %v", tt.strategy, ids, tt.want)
+		}
+	}
+}
+
+func TestInvalidIDStrategy(t *testing.T) {
+	for _, strategy := range []string{"kind", "{{.Name", "{{.Missing}}"} {
+		if _, err := Convert(context.Background(), strings.NewReader(sharedNames), Options{IDStrategy: strategy}); err == nil {
+			t.Errorf("%q: expected an error", strategy)
+		}
+	}
+}
+
+func TestUnnamedIDs(t *testing.T) {
+	manifests := `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  generateName: migrate-
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  generateName: migrate-
+---
+apiVersion: v1
+kind: List
+items: []
+`
+
+	constructs, err := Convert(context.Background(), strings.NewReader(manifests), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, c := range constructs {
+		ids = append(ids, c.ID)
+	}
+	if want := []string{"migrate", "migrate-job", "list"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("got ids %v, want %v", ids, want)
+	}
+}
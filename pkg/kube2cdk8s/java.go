@@ -0,0 +1,200 @@
+package kube2cdk8s
+
+import (
+	"fmt"
+	"strings"
+)
+
+// javaMapOfLimit is the most entries Map.of accepts, larger maps are built
+// with Map.ofEntries.
+const javaMapOfLimit = 10
+
+// java renders r as a cdk8s Java construct using the generated builders.
+func java(r *resource) string {
+	var b strings.Builder
+	s := r.scope
+
+	b.WriteString(commentBlock(Java, r.comments))
+
+	props := r.props()
+	var patches []*field
+	if s.apiObject {
+		s.cdk8s["ApiObject"] = true
+		s.cdk8s["ApiObjectProps"] = true
+		props, patches = apiObjectProps(r)
+	}
+
+	if r.declare {
+		fmt.Fprintf(&b, "final %s %s = ", s.className(), r.variable)
+	}
+	fmt.Fprintf(&b, "new %s(this, %s, ", s.className(), quote(r.id))
+	javaBuilder(&b, s, s.propsType(), props, 0)
+	b.WriteString(")")
+
+	if len(patches) > 0 {
+		s.cdk8s["JsonPatch"] = true
+		if r.declare {
+			// addJsonPatch returns nothing to assign
+			b.WriteString(";\n" + r.variable)
+		}
+		b.WriteString(".addJsonPatch(")
+		for i, f := range patches {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			writeComments(&b, Java, f.value.comments, 1)
+			b.WriteString("\n" + indent(1) + "JsonPatch.add(" + quote(jsonPointer(f.key)) + ", ")
+			javaLiteral(&b, f.value, 1)
+			b.WriteString(")")
+		}
+		b.WriteString(")")
+	}
+	b.WriteString(";\n")
+
+	return b.String()
+}
+
+// javaBuilder writes n as a builder chain of struct name declared in scope s.
+func javaBuilder(b *strings.Builder, s *scope, name string, n *node, level int) {
+	b.WriteString(name + ".builder()")
+	if len(n.fields) == 0 {
+		b.WriteString(".build()")
+		return
+	}
+
+	for _, f := range n.fields {
+		writeComments(b, Java, f.value.comments, level+1)
+		b.WriteString("\n" + indent(level+1) + "." + jsiiName(f.key) + "(")
+		javaValue(b, s, s.fieldType(name, f.key, f.value), f.value, level+1)
+		b.WriteString(")")
+	}
+	b.WriteString("\n" + indent(level+1) + ".build()")
+}
+
+// javaValue writes n as a Java expression of type t indented at level.
+func javaValue(b *strings.Builder, s *scope, t typeRef, n *node, level int) {
+	switch {
+	case t.kind == structType && n.kind == mapNode:
+		s.moduleOf(t) // records the cdk8s core types a chart imports
+		javaBuilder(b, s, t.name, n, level)
+
+	case t.kind == listType && n.kind == listNode:
+		javaList(b, n.items, level, func(item *node, level int) {
+			javaValue(b, s, *t.elem, item, level)
+		})
+
+	case t.kind == mapType && n.kind == mapNode:
+		javaMap(b, n, level, func(item *node, level int) {
+			javaValue(b, s, *t.elem, item, level)
+		})
+
+	case t.kind == quantityType:
+		javaUnion(b, "Quantity", n)
+
+	case t.kind == intOrStringType:
+		javaUnion(b, "IntOrString", n)
+
+	default:
+		javaLiteral(b, n, level)
+	}
+}
+
+// javaUnion writes n through the fromNumber or fromString factory of a cdk8s
+// union class such as Quantity.
+func javaUnion(b *strings.Builder, class string, n *node) {
+	switch n.kind {
+	case intNode, floatNode:
+		fmt.Fprintf(b, "%s.fromNumber(%s)", class, n.value)
+	default:
+		fmt.Fprintf(b, "%s.fromString(%s)", class, quote(n.value))
+	}
+}
+
+// javaLiteral writes n as a plain Java value.
+func javaLiteral(b *strings.Builder, n *node, level int) {
+	switch n.kind {
+	case nullNode:
+		b.WriteString("null")
+	case stringNode:
+		b.WriteString(quote(n.value))
+	case mapNode:
+		javaMap(b, n, level, func(item *node, level int) {
+			javaLiteral(b, item, level)
+		})
+	case listNode:
+		javaList(b, n.items, level, func(item *node, level int) {
+			javaLiteral(b, item, level)
+		})
+	default:
+		b.WriteString(n.value)
+	}
+}
+
+// javaList writes items as a List.of call. List.of rejects nulls, so lists
+// holding one are written as an Arrays.asList call instead.
+func javaList(b *strings.Builder, items []*node, level int, item func(n *node, level int)) {
+	list := "List.of("
+	for _, n := range items {
+		if n.kind == nullNode {
+			list = "java.util.Arrays.asList("
+		}
+	}
+
+	b.WriteString(list)
+	switch {
+	case len(items) == 0:
+	case len(items) == 1 && len(items[0].comments) == 0:
+		item(items[0], level)
+	default:
+		for i, n := range items {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			writeComments(b, Java, n.comments, level+1)
+			b.WriteString("\n" + indent(level+1))
+			item(n, level+1)
+		}
+	}
+	b.WriteString(")")
+}
+
+// javaMap writes n as a Map.of call, or Map.ofEntries when it has more
+// entries than Map.of takes. Map.of rejects nulls, so null entries, which
+// kubernetes treats as unset, are left out.
+func javaMap(b *strings.Builder, n *node, level int, value func(n *node, level int)) {
+	var fields []*field
+	for _, f := range n.fields {
+		if f.value.kind != nullNode {
+			fields = append(fields, f)
+		}
+	}
+
+	if len(fields) > javaMapOfLimit {
+		b.WriteString("Map.ofEntries(")
+		for i, f := range fields {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			writeComments(b, Java, f.value.comments, level+1)
+			b.WriteString("\n" + indent(level+1) + "Map.entry(" + quote(f.key) + ", ")
+			value(f.value, level+1)
+			b.WriteString(")")
+		}
+		b.WriteString(")")
+		return
+	}
+
+	b.WriteString("Map.of(")
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		if len(fields) > 1 || len(f.value.comments) > 0 {
+			writeComments(b, Java, f.value.comments, level+1)
+			b.WriteString("\n" + indent(level+1))
+		}
+		b.WriteString(quote(f.key) + ", ")
+		value(f.value, level+1)
+	}
+	b.WriteString(")")
+}
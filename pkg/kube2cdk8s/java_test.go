@@ -0,0 +1,146 @@
+package kube2cdk8s
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/smallcase/kube2cdk8s/util"
+
+	"github.com/bradleyjkemp/cupaloy"
+)
+
+func TestKube2CDK8SJavaDeployment(t *testing.T) {
+
+	deployment := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-deployment
+  namespace: my-namespace
+spec:
+  selector:
+    matchLabels:
+      app: my-deployment
+  replicas: 3
+  template:
+    metadata:
+      labels:
+        app: my-deployment
+        tier: backend
+    spec:
+      containers:
+      - name: my-deployment
+        image: my-image
+        imagePullPolicy: Always
+        ports:
+        - containerPort: 8080
+        env:
+        - name: A
+          value: "1"
+        - name: B
+          value: "2"
+        resources:
+          limits:
+            cpu: 500m
+`
+	deploymentFile, err := util.CreateTempFile([]byte(deployment))
+	if err != nil {
+		log.Println(err.Error())
+	}
+
+	d, err := Kube2CDK8SLanguage(deploymentFile.Name(), Java)
+	if err != nil {
+		log.Println(err.Error())
+	}
+
+	err = cupaloy.Snapshot(d)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	defer os.Remove(deploymentFile.Name())
+}
+
+func TestKube2CDK8SJavaMultiple(t *testing.T) {
+
+	manifests := `---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: my-service-account
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: my-service
+spec:
+  selector:
+    app: my-app
+  ports:
+  - port: 80
+    targetPort: http
+`
+	manifestsFile, err := util.CreateTempFile([]byte(manifests))
+	if err != nil {
+		log.Println(err.Error())
+	}
+
+	d, err := Kube2CDK8SMultipleLanguage(manifestsFile.Name(), Java)
+	if err != nil {
+		log.Println(err.Error())
+	}
+
+	err = cupaloy.Snapshot(d)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	defer os.Remove(manifestsFile.Name())
+}
+
+func TestKube2CDK8SJavaNulls(t *testing.T) {
+
+	// Map.of and List.of throw on nulls at synth
+	manifests := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-config-map
+  annotations:
+    example.com/owner: payments
+    example.com/unset: null
+data:
+  key: value
+  empty: ~
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: my-widget
+spec:
+  sizes: [small, null, large]
+  limits:
+    cpu: null
+`
+
+	constructs, err := Convert(context.Background(), strings.NewReader(manifests), Options{Language: Java, APIObjects: []string{"Widget"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code := Join(constructs, Java)
+	if strings.Contains(code, "example.com/unset") {
+		t.Errorf("null is passed to Map.of:\n%s", code)
+	}
+	if !strings.Contains(code, "java.util.Arrays.asList(") {
+		t.Errorf("null list items are left out:\n%s", code)
+	}
+
+	err = cupaloy.Snapshot(code)
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
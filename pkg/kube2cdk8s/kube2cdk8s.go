@@ -1,106 +1,498 @@
 package kube2cdk8s
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
 	"regexp"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+)
+
+// Language is a cdk8s target language.
+type Language string
 
-	"github.com/pulumi/kube2pulumi/pkg/kube2pulumi"
-	"github.com/smallcase/kube2cdk8s/util"
-	"github.com/spf13/viper"
+const (
+	TypeScript Language = "typescript"
+	Python     Language = "python"
+	Go         Language = "go"
+	Java       Language = "java"
+	CSharp     Language = "csharp"
 )
 
+var generators = map[Language]func(r *resource) string{
+	TypeScript: typescript,
+	Python:     python,
+	Go:         golang,
+	Java:       java,
+	CSharp:     csharp,
+}
+
+// DefaultImportAlias is the name the generated cdk8s bindings are imported
+// as unless Options.ImportAlias says otherwise.
+const DefaultImportAlias = "k8s"
+
+// defaultImportPaths are the modules the generated cdk8s bindings are
+// imported from by default, where cdk8s import writes them.
+var defaultImportPaths = map[Language]string{
+	TypeScript: "./imports/k8s",
+	Python:     "imports.k8s",
+	Go:         "example.com/app/imports/k8s",
+	Java:       "imports.k8s",
+	CSharp:     "Imports.K8s",
+}
+
+var importAliasPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Comment returns text as a line comment in language.
+func (l Language) Comment(text string) string {
+	if l == Python {
+		return "# " + text
+	}
+
+	return "// " + text
+}
+
+// Kube2CDK8S converts the manifest in filePath to a cdk8s TypeScript
+// construct. The kind and name of the construct are read from the manifest
+// itself, so any path can be converted and conversions can run concurrently.
 func Kube2CDK8S(filePath string) (string, error) {
-	path, _, err := kube2pulumi.Kube2PulumiFile(filePath, "", "typescript")
+	return Kube2CDK8SLanguage(filePath, TypeScript)
+}
+
+// Kube2CDK8SMultiple converts every document of the YAML stream in filePath to
+// cdk8s TypeScript constructs.
+func Kube2CDK8SMultiple(filePath string) (string, error) {
+	return Kube2CDK8SMultipleLanguage(filePath, TypeScript)
+}
+
+// Kube2CDK8SLanguage converts the manifest in filePath to a cdk8s construct
+// in language.
+func Kube2CDK8SLanguage(filePath string, language Language) (string, error) {
+	f, err := os.Open(filePath)
 	if err != nil {
 		return "", err
 	}
+	defer f.Close()
+
+	result, err := Kube2CDK8SReader(f, language)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", filePath, err)
+	}
+
+	return result, nil
+}
 
-	input, err := os.ReadFile(path)
+// Kube2CDK8SMultipleLanguage converts every document of the YAML stream in
+// filePath to cdk8s constructs in language.
+func Kube2CDK8SMultipleLanguage(filePath string, language Language) (string, error) {
+	f, err := os.Open(filePath)
 	if err != nil {
 		return "", err
 	}
+	defer f.Close()
 
-	lines := strings.Split(string(input), "\n")
+	result, err := Kube2CDK8SMultipleReader(f, language)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", filePath, err)
+	}
 
-	for i, line := range lines {
-		if strings.Contains(line, "import") {
-			lines[i] = ``
-		}
+	return result, nil
+}
+
+// Kube2CDK8SReader converts the manifest read from r to a cdk8s construct in
+// language.
+func Kube2CDK8SReader(r io.Reader, language Language) (string, error) {
+	generate, ok := generators[language]
+	if !ok {
+		return "", fmt.Errorf("unsupported language: %s", language)
 	}
-	output := strings.Join(lines, "\n")
 
-	viper.New()
-	viper.AddConfigPath("/tmp")
-	viper.SetConfigName(filepath.Base(filePath))
-	viper.SetConfigType("yaml")
+	input, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
 
-	if err := viper.ReadInConfig(); err != nil {
+	res, err := parseResource(input)
+	if err != nil {
 		return "", err
 	}
 
-	n := viper.GetString("metadata.name")
-	k := viper.GetString("kind")
+	opts := Options{Language: language}
+	if err := opts.assignIDs([]*resource{res}); err != nil {
+		return "", err
+	}
 
-	name := fmt.Sprintf("new k8s.Kube%s(this, \"%s\", {", k, n)
+	opts, err = opts.withModuleAliases([]*resource{res})
+	if err != nil {
+		return "", err
+	}
 
-	re := regexp.MustCompile("(?m)[\r\n]+^.*const.*$")
-	res := re.ReplaceAllString(output, name)
+	return opts.construct(res, language, generate).Code, nil
+}
+
+// Kube2CDK8SMultipleReader converts every document of the YAML stream read
+// from r to cdk8s constructs in language.
+func Kube2CDK8SMultipleReader(r io.Reader, language Language) (string, error) {
+	var result string
 
-	re2 := regexp.MustCompile("(?m)[\r\n]+^.*apiVersion.*$")
-	loc := re2.FindStringIndex(res)
-	res2 := res
-	if loc != nil {
-		res2 = res[:loc[0]] + res[loc[1]:]
+	constructs, err := Convert(context.Background(), r, Options{Language: language})
+	if err != nil {
+		return "", err
 	}
 
-	re3 := regexp.MustCompile("(?m)[\r\n]+^.*kind.*$")
-	loc = re3.FindStringIndex(res2)
-	res3 := res2
-	if loc != nil {
-		res3 = res2[:loc[0]] + res2[loc[1]:]
+	for _, c := range constructs {
+		result += c.Code
+		result += "\n"
 	}
 
-	defer os.Remove(path)
+	return result, nil
+}
+
+// Options configures Convert.
+type Options struct {
+	// Language is the language constructs are generated in, TypeScript if
+	// empty.
+	Language Language
+
+	// Jobs is the number of documents converted in parallel, the number of
+	// CPUs if zero.
+	Jobs int
+
+	// ImportAlias is the name the generated cdk8s bindings are imported as
+	// and every class is qualified with, DefaultImportAlias if empty. Java
+	// and C# import the classes unqualified and ignore it.
+	ImportAlias string
+
+	// ImportPath is the module the generated cdk8s bindings are imported
+	// from in a chart, where cdk8s import writes them for the language if
+	// empty.
+	ImportPath string
+
+	// Modules maps API groups of custom resources to the modules cdk8s
+	// import generated for them. Groups that are not listed are imported
+	// from the module cdk8s import names after the group.
+	Modules map[string]Module
+
+	// APIObjects lists the kinds generated as plain cdk8s ApiObjects rather
+	// than with their class, written as Kind or as group/Kind, e.g.
+	// apps/Deployment. Kinds of the builtin API groups that are missing
+	// from the k8s bindings are always generated as ApiObjects.
+	APIObjects []string
+
+	// KubernetesVersion is the version of Kubernetes the k8s bindings were
+	// imported for, e.g. 1.22. When set, objects whose API version it does
+	// not serve fail to convert instead of referring to classes missing
+	// from the bindings.
+	KubernetesVersion string
+
+	// IDStrategy is how construct IDs are made from objects: IDName, the
+	// default, IDKindName, IDNamespaceKindName or a text/template rendered
+	// with an IDData, e.g. {{.Namespace}}-{{.Name}}. IDs already given to
+	// an earlier object are made unique.
+	IDStrategy string
 
-	return res3, nil
+	// Target is the kind of constructs generated, TargetK8s if empty.
+	// TargetPlus is only supported in TypeScript.
+	Target Target
+
+	// Variables assigns every construct to a variable named after the kind
+	// and name of its object, e.g. apiDeployment, so that code added to the
+	// chart can refer to it.
+	Variables bool
+
+	// Params lifts values of the objects into props of the chart, which the
+	// constructs read instead. Each is either a shorthand, namespace,
+	// replicas, image, tag, limits or requests, or name=selector where the
+	// selector is a JSONPath-like path such as
+	// spec.template.spec.containers[0].resources.limits.cpu. Params are only
+	// supported in TypeScript.
+	Params []string
 }
 
-func Kube2CDK8SMultiple(filePath string) (string, error) {
-	var result string
+// importAlias returns the alias set in opts, or the default one.
+func (opts Options) importAlias() (string, error) {
+	if opts.ImportAlias == "" {
+		return DefaultImportAlias, nil
+	}
+
+	if !importAliasPattern.MatchString(opts.ImportAlias) {
+		return "", fmt.Errorf("invalid import alias %q", opts.ImportAlias)
+	}
+
+	return opts.ImportAlias, nil
+}
+
+// importPath returns the import path set in opts, or the default one of
+// language.
+func (opts Options) importPath(language Language) string {
+	if opts.ImportPath == "" {
+		return defaultImportPaths[language]
+	}
+
+	return opts.ImportPath
+}
+
+// Construct is the cdk8s construct generated for a single kubernetes object.
+type Construct struct {
+	APIVersion string
+	Kind       string
+	Name       string
+	Code       string
+
+	// ID is the construct ID the code creates the construct with.
+	ID string
+
+	// Variable is the variable the code assigns the construct to, empty
+	// unless Options.Variables is set.
+	Variable string
+
+	// Source is the file the object was read from, empty when it was read
+	// from a reader.
+	Source string
 
-	input, err := os.ReadFile(filePath)
+	// read is set when the code of a later construct reads Variable.
+	read bool
+
+	// module is the module the construct's class is imported from, empty
+	// for an ApiObject.
+	module Module
+
+	// cdk8s lists the cdk8s core types the code refers to.
+	cdk8s []string
+
+	// unsupported lists the fields that kept the object from being
+	// generated with cdk8s-plus.
+	unsupported []string
+
+	// params lists the params the code reads.
+	params []Param
+}
+
+// Unsupported returns the fields of the object that cdk8s-plus cannot
+// express, which made it fall back to the class of the k8s bindings. It is
+// empty unless Options.Target is TargetPlus.
+func (c Construct) Unsupported() []string {
+	return c.unsupported
+}
+
+// Params returns the params of the chart the code reads instead of the
+// values of the object. It is empty unless Options.Params is set.
+func (c Construct) Params() []Param {
+	return c.params
+}
+
+// DocumentError is the error converting a single document of a manifest.
+type DocumentError struct {
+	// Source is the file the document was read from, if any.
+	Source string
+
+	// Document is the position of the document in the manifest, starting
+	// at 1.
+	Document int
+	Err      error
+}
+
+func (e *DocumentError) Error() string {
+	if e.Source != "" {
+		return fmt.Sprintf("%s: document %d: %v", e.Source, e.Document, e.Err)
+	}
+
+	return fmt.Sprintf("document %d: %v", e.Document, e.Err)
+}
+
+func (e *DocumentError) Unwrap() error {
+	return e.Err
+}
+
+// Errors holds the error of every document that failed to convert.
+type Errors []*DocumentError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// Convert converts every document of the YAML stream read from r to cdk8s
+// constructs. Documents are converted in parallel by opts.Jobs workers and
+// the constructs are returned in the order of the manifests. If any document
+// fails to convert, the returned error is an Errors listing all of them.
+func Convert(ctx context.Context, r io.Reader, opts Options) ([]Construct, error) {
+	input, err := io.ReadAll(r)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	m := strings.Split(string(input), "---")
+	docs, decodeErr := decodeSource("", input)
 
-	for _, v := range m {
+	var errs Errors
+	if decodeErr != nil {
+		errs = append(errs, decodeErr)
+	}
 
-		if v == "" {
-			continue
+	return convertDocuments(ctx, docs, errs, opts)
+}
+
+// construct generates the construct of res in language with generate, or
+// with cdk8s-plus when it was planned so.
+func (opts Options) construct(res *resource, language Language, generate func(r *resource) string) Construct {
+	s, module := opts.scope(res, language)
+	res.scope = s
+
+	var variable string
+	if res.declare {
+		variable = res.variable
+	}
+
+	var code string
+	var unsupported []string
+	switch p := res.plus; {
+	case p != nil && p.supported():
+		code, module = tsPlus(res), opts.plusModule()
+		for name := range p.cdk8s {
+			s.cdk8s[name] = true
 		}
-		f, err := util.CreateTempFile([]byte(v))
-		if err != nil {
-			return "", err
+	case p != nil:
+		unsupported = p.unsupported
+		code = language.Comment("cdk8s-plus cannot express "+strings.Join(p.unsupported, ", ")) + "\n" + generate(res)
+	case len(s.unknown) > 0:
+		code = language.Comment("no known k8s type for "+strings.Join(s.unknown, ", ")) + "\n" + generate(res)
+	default:
+		code = generate(res)
+	}
+
+	return Construct{
+		APIVersion:  res.apiVersion,
+		Kind:        res.kind,
+		Name:        res.name,
+		Code:        code,
+		ID:          res.id,
+		Variable:    variable,
+		read:        res.plus != nil && res.plus.supported() && res.plus.referenced,
+		module:      module,
+		cdk8s:       sortedKeys(s.cdk8s),
+		unsupported: unsupported,
+		params:      res.params,
+	}
+}
+
+// forEach calls f with every index below n from a pool of jobs workers, and
+// stops early when ctx is done.
+func forEach(ctx context.Context, jobs, n int, f func(i int)) error {
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < jobs && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				f(i)
+			}
+		}()
+	}
+
+send:
+	for i := 0; i < n; i++ {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break send
 		}
+	}
+	close(indexes)
+	wg.Wait()
 
-		res, err := Kube2CDK8S(f.Name())
-		if err != nil {
-			return "", err
+	return ctx.Err()
+}
+
+// convertDocuments converts docs with a pool of opts.Jobs workers. errs are
+// errors of documents that could not be decoded, which are reported along
+// with the conversion errors.
+func convertDocuments(ctx context.Context, docs []document, errs Errors, opts Options) ([]Construct, error) {
+	language := opts.Language
+	if language == "" {
+		language = TypeScript
+	}
+
+	generate, ok := generators[language]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language: %s", language)
+	}
+
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	jobs := opts.Jobs
+	if jobs < 1 {
+		jobs = runtime.NumCPU()
+	}
+
+	resources := make([]*resource, len(docs))
+	docErrs := make([]error, len(docs))
+
+	err := forEach(ctx, jobs, len(docs), func(i int) {
+		res, err := parseDocument(docs[i].node)
+		if err == nil {
+			err = opts.checkAPIVersion(res)
 		}
+		resources[i], docErrs[i] = res, err
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		result += res
-		result += "\n"
+	if err := opts.assignIDs(resources); err != nil {
+		return nil, err
+	}
+
+	opts, err = opts.withModuleAliases(resources)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.assignVariables(resources, language)
+
+	if opts.Target == TargetPlus {
+		planPlus(resources)
+	}
 
-		err = os.Remove(f.Name())
+	if err := opts.liftParams(resources); err != nil {
+		return nil, err
+	}
+
+	constructs := make([]Construct, len(docs))
+	err = forEach(ctx, jobs, len(docs), func(i int) {
+		if resources[i] != nil && docErrs[i] == nil {
+			constructs[i] = opts.construct(resources[i], language, generate)
+			constructs[i].Source = docs[i].source
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, err := range docErrs {
 		if err != nil {
-			return "", err
+			errs = append(errs, &DocumentError{Source: docs[i].source, Document: docs[i].index, Err: err})
 		}
 	}
+	if len(errs) > 0 {
+		sort.SliceStable(errs, func(i, j int) bool {
+			if errs[i].Source != errs[j].Source {
+				return errs[i].Source < errs[j].Source
+			}
+			return errs[i].Document < errs[j].Document
+		})
+		return nil, errs
+	}
 
-	return result, nil
+	return constructs, nil
 }
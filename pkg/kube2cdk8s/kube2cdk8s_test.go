@@ -1,8 +1,15 @@
 package kube2cdk8s
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/smallcase/kube2cdk8s/util"
@@ -313,3 +320,645 @@ subjects:
 
 	defer os.Remove(roleFile.Name())
 }
+
+func TestKube2CDK8SConfigMap(t *testing.T) {
+
+	configMap := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-config-map
+  annotations:
+    example.com/owner: platform
+data:
+  replicas: "3"
+  enabled: "true"
+  nginx.conf: |
+    server {
+      listen 80;
+      root ${ROOT};
+    }
+`
+	configMapFile, err := util.CreateTempFile([]byte(configMap))
+	if err != nil {
+		log.Println(err.Error())
+	}
+
+	d, err := Kube2CDK8S(configMapFile.Name())
+	if err != nil {
+		log.Println(err.Error())
+	}
+
+	err = cupaloy.Snapshot(d)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	defer os.Remove(configMapFile.Name())
+}
+
+func TestKube2CDK8SReader(t *testing.T) {
+
+	serviceAccount := `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: my-service-account
+  namespace: my-namespace
+`
+	d, err := Kube2CDK8SReader(strings.NewReader(serviceAccount), TypeScript)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	f, err := Kube2CDK8S(writeTempFile(t, serviceAccount))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if d != f {
+		t.Errorf("reader output differs from file output:\n%s\n%s", d, f)
+	}
+}
+
+func writeTempFile(t *testing.T, text string) string {
+	t.Helper()
+
+	f, err := util.CreateTempFile([]byte(text))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Cleanup(func() { os.Remove(f.Name()) })
+
+	return f.Name()
+}
+
+func TestConvert(t *testing.T) {
+
+	manifests := `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: my-service-account
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-deployment
+`
+	constructs, err := Convert(context.Background(), strings.NewReader(manifests), Options{Language: Python})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expected := []Construct{
+		{APIVersion: "v1", Kind: "ServiceAccount", Name: "my-service-account"},
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "my-deployment"},
+	}
+
+	if len(constructs) != len(expected) {
+		t.Fatalf("expected %d constructs, got %d", len(expected), len(constructs))
+	}
+
+	for i, c := range constructs {
+		if c.APIVersion != expected[i].APIVersion || c.Kind != expected[i].Kind || c.Name != expected[i].Name {
+			t.Errorf("construct %d: expected %s %s/%s, got %s %s/%s", i,
+				expected[i].APIVersion, expected[i].Kind, expected[i].Name, c.APIVersion, c.Kind, c.Name)
+		}
+
+		if !strings.HasPrefix(c.Code, "k8s.Kube"+c.Kind+"(self, ") {
+			t.Errorf("construct %d: unexpected code %q", i, c.Code)
+		}
+	}
+}
+
+func TestKube2CDK8SConcurrent(t *testing.T) {
+
+	// every manifest has the same base name in a different directory
+	var paths []string
+	for i := 0; i < 8; i++ {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "service-account.yaml")
+
+		serviceAccount := fmt.Sprintf(`apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: my-service-account-%d
+`, i)
+		if err := os.WriteFile(path, []byte(serviceAccount), 0o600); err != nil {
+			t.Fatal(err.Error())
+		}
+		paths = append(paths, path)
+	}
+
+	var wg sync.WaitGroup
+	results := make([]string, len(paths))
+	errs := make([]error, len(paths))
+	for i, path := range paths {
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			results[i], errs[i] = Kube2CDK8S(path)
+		}(i, path)
+	}
+	wg.Wait()
+
+	for i, d := range results {
+		if errs[i] != nil {
+			t.Fatal(errs[i].Error())
+		}
+
+		expected := fmt.Sprintf(`new k8s.KubeServiceAccount(this, "my-service-account-%d", {`, i)
+		if !strings.HasPrefix(d, expected) {
+			t.Errorf("%s: expected %q, got %q", paths[i], expected, d)
+		}
+	}
+}
+
+func TestConvertJobs(t *testing.T) {
+
+	manifests := deployments(50)
+
+	sequential, err := Convert(context.Background(), strings.NewReader(manifests), Options{Jobs: 1})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	parallel, err := Convert(context.Background(), strings.NewReader(manifests), Options{Jobs: 8})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(parallel) != 50 {
+		t.Fatalf("expected 50 constructs, got %d", len(parallel))
+	}
+
+	for i := range sequential {
+		if !reflect.DeepEqual(parallel[i], sequential[i]) {
+			t.Errorf("construct %d differs between 1 and 8 jobs", i)
+		}
+
+		if name := fmt.Sprintf("my-deployment-%d", i); parallel[i].Name != name {
+			t.Errorf("construct %d: expected %s, got %s", i, name, parallel[i].Name)
+		}
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+
+	manifests := `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: my-service-account
+---
+apiVersion: v1
+metadata:
+  name: no-kind
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-config-map
+---
+kind: [
+`
+	_, err := Convert(context.Background(), strings.NewReader(manifests), Options{Jobs: 4})
+
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected Errors, got %v", err)
+	}
+
+	if len(errs) != 2 || errs[0].Document != 2 || errs[1].Document != 4 {
+		t.Errorf("expected errors for documents 2 and 4, got %v", err)
+	}
+}
+
+func TestConvertErrorsEmptyDocuments(t *testing.T) {
+
+	manifests := `---
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: my-service-account
+---
+# comment only document
+---
+apiVersion: v1
+metadata:
+  name: no-kind
+---
+kind: [
+`
+	_, err := Convert(context.Background(), strings.NewReader(manifests), Options{})
+
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected Errors, got %v", err)
+	}
+
+	if len(errs) != 2 || errs[0].Document != 4 || errs[1].Document != 5 {
+		t.Errorf("expected errors for documents 4 and 5, got %v", err)
+	}
+}
+
+func BenchmarkConvert(b *testing.B) {
+	manifests := deployments(300)
+
+	for _, jobs := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("jobs=%d", jobs), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, err := Convert(context.Background(), strings.NewReader(manifests), Options{Language: Go, Jobs: jobs})
+				if err != nil {
+					b.Fatal(err.Error())
+				}
+			}
+		})
+	}
+}
+
+// deployments returns a manifest of n deployments separated by ---.
+func deployments(n int) string {
+	var manifests strings.Builder
+
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&manifests, `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-deployment-%[1]d
+  namespace: my-namespace
+spec:
+  selector:
+    matchLabels:
+      app: my-deployment-%[1]d
+  replicas: 3
+  template:
+    metadata:
+      labels:
+        app: my-deployment-%[1]d
+    spec:
+      containers:
+      - name: my-deployment-%[1]d
+        image: my-image
+        imagePullPolicy: Always
+        ports:
+        - containerPort: 8080
+        resources:
+          limits:
+            cpu: 500m
+            memory: 1Gi
+`, i)
+	}
+
+	return manifests.String()
+}
+
+func TestKube2CDK8SMultipleDocumentMarkers(t *testing.T) {
+
+	manifests := `# comment only document
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-config-map
+data:
+  README.md: |
+    # Title
+    ---
+    Text below a horizontal rule.
+  tls.crt: |
+    -----BEGIN CERTIFICATE-----
+    MIIBszCCAVmgAwIBAgIUdGVzdA==
+    -----END CERTIFICATE-----
+...
+---
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: my-service-account
+...
+`
+	manifestsFile, err := util.CreateTempFile([]byte(manifests))
+	if err != nil {
+		log.Println(err.Error())
+	}
+
+	d, err := Kube2CDK8SMultiple(manifestsFile.Name())
+	if err != nil {
+		log.Println(err.Error())
+	}
+
+	err = cupaloy.Snapshot(d)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	defer os.Remove(manifestsFile.Name())
+}
+
+func TestKube2CDK8SRejectsMultipleDocuments(t *testing.T) {
+
+	_, err := Kube2CDK8S(writeTempFile(t, deployments(2)))
+	if err == nil {
+		t.Fatal("expected an error for a file with two documents")
+	}
+
+	if !strings.Contains(err.Error(), "found 2 documents") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestKube2CDK8SRoleBinding(t *testing.T) {
+
+	roleBinding := `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: read-pods
+  namespace: default
+subjects:
+- kind: User
+  name: jane
+  apiGroup: rbac.authorization.k8s.io
+- kind: ServiceAccount
+  name: my-service-account
+  namespace: default
+roleRef:
+  kind: Role
+  name: pod-reader
+  apiGroup: rbac.authorization.k8s.io
+`
+	roleBindingFile := writeTempFile(t, roleBinding)
+
+	d, err := Kube2CDK8S(roleBindingFile)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = cupaloy.Snapshot(d)
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestKube2CDK8SClusterRoleBinding(t *testing.T) {
+
+	clusterRoleBinding := `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: read-secrets-global
+subjects:
+- kind: Group
+  name: manager
+  apiGroup: rbac.authorization.k8s.io
+roleRef:
+  kind: ClusterRole
+  name: secret-reader
+  apiGroup: rbac.authorization.k8s.io
+`
+	clusterRoleBindingFile := writeTempFile(t, clusterRoleBinding)
+
+	d, err := Kube2CDK8SLanguage(clusterRoleBindingFile, Python)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err = cupaloy.Snapshot(d)
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestKube2CDK8SKindInLabels(t *testing.T) {
+
+	// kind and apiVersion appear as label values, annotation keys and
+	// owner references, and must all be kept
+	configMap := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kind-config
+  labels:
+    app.kubernetes.io/component: kind-cluster
+    kind: settings
+  annotations:
+    apiVersion: v1
+  ownerReferences:
+  - apiVersion: apps/v1
+    kind: Deployment
+    name: my-deployment
+    uid: d9607e19-f88f-11e6-a518-42010a800195
+data:
+  kind: ConfigMap
+`
+	configMapFile := writeTempFile(t, configMap)
+
+	d, err := Kube2CDK8S(configMapFile)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if strings.Count(d, "kind") != 6 || strings.Count(d, "apiVersion") != 2 {
+		t.Errorf("nested kind or apiVersion fields were dropped:\n%s", d)
+	}
+
+	err = cupaloy.Snapshot(d)
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestKube2CDK8SComments(t *testing.T) {
+
+	deployment := `# deploys the web frontend
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web # the service selects on this name
+  labels:
+    # owning team
+    team: frontend
+spec:
+  # two replicas survive a node drain
+  replicas: 2
+  template:
+    spec:
+      containers:
+      # the only container
+      - name: web
+        image: nginx # pinned by digest in production
+        resources:
+          # limits keep the "noisy" neighbours in check
+          limits:
+            cpu: 500m
+      tolerations:
+      - key: dedicated
+      # runs on the ` + "`frontend`" + ` pool
+      - key: frontend
+`
+	deploymentFile := writeTempFile(t, deployment)
+
+	for _, language := range []Language{TypeScript, Python, Go, Java, CSharp} {
+		d, err := Kube2CDK8SLanguage(deploymentFile, language)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		err = cupaloy.SnapshotMulti(string(language), d)
+		if err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
+
+func TestUnknownStructFields(t *testing.T) {
+
+	// the types of neither field are known, so the object is an ApiObject
+	// rather than a struct made up
+	manifests := `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  topologyHints:
+    mode: auto
+  extraPorts:
+  - port: 80
+`
+
+	for _, language := range []Language{TypeScript, Python, Go, Java, CSharp} {
+		constructs, err := Convert(context.Background(), strings.NewReader(manifests), Options{Language: language})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = cupaloy.SnapshotMulti(string(language), Join(constructs, language))
+		if err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
+
+func TestQuantityMaps(t *testing.T) {
+	manifests := `
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: compute
+spec:
+  hard:
+    requests.cpu: "4"
+    requests.memory: 8Gi
+---
+apiVersion: v1
+kind: PersistentVolume
+metadata:
+  name: data
+spec:
+  capacity:
+    storage: 10Gi
+  accessModes:
+  - ReadWriteOnce
+  awsElasticBlockStore:
+    volumeID: vol-0123456789
+    fsType: ext4
+`
+
+	for _, language := range []Language{TypeScript, Python, Go, Java, CSharp} {
+		constructs, err := Convert(context.Background(), strings.NewReader(manifests), Options{Language: language})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = cupaloy.SnapshotMulti(string(language), Join(constructs, language))
+		if err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
+
+func TestPropertyNames(t *testing.T) {
+
+	// cdk8s import lowercases acronyms in properties, not in label keys
+	manifests := `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  labels:
+    exposeIP: "false"
+spec:
+  clusterIP: None
+  clusterIPs:
+  - None
+  ports:
+  - port: 80
+`
+
+	for _, language := range []Language{TypeScript, Python, Go, Java, CSharp} {
+		constructs, err := Convert(context.Background(), strings.NewReader(manifests), Options{Language: language})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = cupaloy.SnapshotMulti(string(language), Join(constructs, language))
+		if err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
+
+func TestKubectlOutput(t *testing.T) {
+
+	// kubectl create deployment web --image=nginx --dry-run=client -o yaml
+	manifests := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  creationTimestamp: null
+  labels:
+    app: web
+  name: web
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: web
+  strategy: {}
+  template:
+    metadata:
+      creationTimestamp: null
+      labels:
+        app: web
+    spec:
+      containers:
+      - image: nginx
+        name: nginx
+        resources: {}
+status: {}
+`
+
+	for _, language := range []Language{TypeScript, Python, Go, Java, CSharp} {
+		constructs, err := Convert(context.Background(), strings.NewReader(manifests), Options{Language: language})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		code := Join(constructs, language)
+		for _, s := range []string{"status", "creationTimestamp", "null", "None", "nil"} {
+			if strings.Contains(strings.ToLower(code), strings.ToLower(s)) {
+				t.Errorf("%s output contains %s:\n%s", language, s, code)
+			}
+		}
+
+		err = cupaloy.SnapshotMulti(string(language), code)
+		if err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
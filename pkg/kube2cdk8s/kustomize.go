@@ -0,0 +1,82 @@
+package kube2cdk8s
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Kustomization is a kustomization directory, such as an overlay of a base.
+type Kustomization struct {
+	// Path is the directory holding the kustomization.yaml.
+	Path string
+
+	// Binary is the kustomize executable. If empty, kustomize is looked up
+	// in PATH, falling back to kubectl kustomize.
+	Binary string
+}
+
+// Build builds the kustomization locally, applying its resources, patches,
+// name prefixes and suffixes, common labels and generators, and returns the
+// resulting YAML stream.
+func (k Kustomization) Build(ctx context.Context) ([]byte, error) {
+	binary, args := k.Binary, []string{"build", k.Path}
+	if binary == "" {
+		binary = "kustomize"
+		if _, err := exec.LookPath(binary); err != nil {
+			if _, err := exec.LookPath("kubectl"); err == nil {
+				binary, args = "kubectl", []string{"kustomize", k.Path}
+			}
+		}
+	}
+
+	return render(ctx, "kustomization "+k.Path, binary, args)
+}
+
+// ConvertKustomization builds k and converts every resulting document to
+// cdk8s constructs, see Convert. Each construct records the kustomization as
+// its Source.
+func ConvertKustomization(ctx context.Context, k Kustomization, opts Options) ([]Construct, error) {
+	// fail before running the tool rather than after
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	built, err := k.Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, decodeErr := decodeSource(k.Path, built)
+
+	var errs Errors
+	if decodeErr != nil {
+		errs = append(errs, decodeErr)
+	}
+
+	return convertDocuments(ctx, docs, errs, opts)
+}
+
+// render runs binary with args and returns what it writes to stdout. what
+// names the input being rendered in errors.
+func render(ctx context.Context, what, binary string, args []string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("rendering %s: %w, install %s to convert it", what, err, binary)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("rendering %s: %s", what, msg)
+		}
+		return nil, fmt.Errorf("rendering %s: %w", what, err)
+	}
+
+	return stdout.Bytes(), nil
+}
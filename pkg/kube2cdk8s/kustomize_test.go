@@ -0,0 +1,62 @@
+package kube2cdk8s
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bradleyjkemp/cupaloy"
+)
+
+const builtKustomization = `apiVersion: v1
+data:
+  LOG_LEVEL: info
+kind: ConfigMap
+metadata:
+  labels:
+    env: prod
+  name: prod-api-config-5k2m8fh7b4
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    env: prod
+  name: prod-api
+spec:
+  replicas: 3
+  selector:
+    matchLabels:
+      env: prod
+`
+
+func TestConvertKustomization(t *testing.T) {
+	binary, args := fakeTool(t, "kustomize", builtKustomization)
+
+	k := Kustomization{Path: "overlays/prod", Binary: binary}
+
+	constructs, err := ConvertKustomization(context.Background(), k, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "build overlays/prod"; strings.TrimSpace(string(got)) != want {
+		t.Errorf("got kustomize %s, want kustomize %s", got, want)
+	}
+
+	for _, c := range constructs {
+		if c.Source != k.Path {
+			t.Errorf("%s %s: got source %q, want %q", c.Kind, c.Name, c.Source, k.Path)
+		}
+	}
+
+	err = cupaloy.Snapshot(Join(constructs, TypeScript))
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
@@ -0,0 +1,434 @@
+package kube2cdk8s
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// nodeKind is the type of a value in a parsed manifest.
+type nodeKind int
+
+const (
+	nullNode nodeKind = iota
+	boolNode
+	intNode
+	floatNode
+	stringNode
+	mapNode
+	listNode
+
+	// codeNode and callNode are never parsed but generated in place of
+	// values, see plus.go: a code node is written as is, a call node calls
+	// its value with its items as arguments.
+	codeNode
+	callNode
+)
+
+// node is a typed value of a parsed manifest. Maps keep their keys in the
+// order they appear in the YAML so the generated code reads like the input.
+type node struct {
+	kind   nodeKind
+	value  string
+	fields []*field
+	items  []*node
+
+	// comments are the YAML comments written above or next to the value,
+	// or its key, without the leading #.
+	comments []string
+}
+
+// field is a single key of a map node.
+type field struct {
+	key   string
+	value *node
+}
+
+// get returns the value of key in a map node, or nil if it is not set.
+func (n *node) get(key string) *node {
+	if n == nil || n.kind != mapNode {
+		return nil
+	}
+
+	for _, f := range n.fields {
+		if f.key == key {
+			return f.value
+		}
+	}
+
+	return nil
+}
+
+// str returns the scalar value of key in a map node, or "" if it is not set.
+func (n *node) str(key string) string {
+	v := n.get(key)
+	if v == nil || v.kind == mapNode || v.kind == listNode || v.kind == nullNode {
+		return ""
+	}
+
+	return v.value
+}
+
+// newNode converts a yaml.v3 node into a typed node, resolving aliases and
+// merge keys along the way.
+func newNode(y *yaml.Node) (*node, error) {
+	switch y.Kind {
+	case yaml.DocumentNode:
+		if len(y.Content) == 0 {
+			return &node{kind: nullNode}, nil
+		}
+		return newNode(y.Content[0])
+
+	case yaml.AliasNode:
+		return newNode(y.Alias)
+
+	case yaml.MappingNode:
+		n := &node{kind: mapNode}
+		for i := 0; i+1 < len(y.Content); i += 2 {
+			k, v := y.Content[i], y.Content[i+1]
+
+			if k.ShortTag() == "!!merge" {
+				if err := n.merge(v); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			if k.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("line %d: unsupported non-scalar map key", k.Line)
+			}
+
+			value, err := newNode(v)
+			if err != nil {
+				return nil, err
+			}
+			value.comments = commentLines(k.HeadComment, k.LineComment, v.HeadComment, v.LineComment)
+			n.set(k.Value, value)
+		}
+		return n, nil
+
+	case yaml.SequenceNode:
+		n := &node{kind: listNode}
+		for _, item := range y.Content {
+			value, err := newNode(item)
+			if err != nil {
+				return nil, err
+			}
+			value.comments = commentLines(item.HeadComment, item.LineComment)
+			n.items = append(n.items, value)
+		}
+		return n, nil
+
+	case yaml.ScalarNode:
+		return newScalar(y)
+	}
+
+	return nil, fmt.Errorf("line %d: unsupported yaml node", y.Line)
+}
+
+// newScalar converts a yaml.v3 scalar, normalizing numbers and booleans to
+// the literal form used by every output language.
+func newScalar(y *yaml.Node) (*node, error) {
+	switch y.ShortTag() {
+	case "!!null":
+		return &node{kind: nullNode}, nil
+
+	case "!!bool":
+		var b bool
+		if err := y.Decode(&b); err != nil {
+			return nil, err
+		}
+		return &node{kind: boolNode, value: strconv.FormatBool(b)}, nil
+
+	case "!!int":
+		var i int64
+		if err := y.Decode(&i); err != nil {
+			return &node{kind: intNode, value: y.Value}, nil
+		}
+		return &node{kind: intNode, value: strconv.FormatInt(i, 10)}, nil
+
+	case "!!float":
+		var f float64
+		if err := y.Decode(&f); err != nil {
+			return nil, err
+		}
+		return &node{kind: floatNode, value: strconv.FormatFloat(f, 'g', -1, 64)}, nil
+	}
+
+	return &node{kind: stringNode, value: y.Value}, nil
+}
+
+// commentLines splits YAML comments into lines without the leading #.
+func commentLines(comments ...string) []string {
+	var lines []string
+	for _, c := range comments {
+		if c == "" {
+			continue
+		}
+		for _, line := range strings.Split(c, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			line = strings.TrimPrefix(line, "#")
+			lines = append(lines, strings.TrimPrefix(line, " "))
+		}
+	}
+
+	return lines
+}
+
+// set adds key to a map node, replacing the value if the key already exists.
+func (n *node) set(key string, value *node) {
+	for _, f := range n.fields {
+		if f.key == key {
+			f.value = value
+			return
+		}
+	}
+
+	n.fields = append(n.fields, &field{key: key, value: value})
+}
+
+// merge applies a YAML merge key (<<) to a map node. Keys already set on the
+// map take precedence over merged ones.
+func (n *node) merge(y *yaml.Node) error {
+	sources := []*yaml.Node{y}
+	if y.Kind == yaml.SequenceNode {
+		sources = y.Content
+	}
+
+	for _, s := range sources {
+		m, err := newNode(s)
+		if err != nil {
+			return err
+		}
+		if m.kind != mapNode {
+			return fmt.Errorf("line %d: merge key must reference a map", y.Line)
+		}
+		for _, f := range m.fields {
+			if n.get(f.key) == nil {
+				n.fields = append(n.fields, f)
+			}
+		}
+	}
+
+	return nil
+}
+
+// resource is a single kubernetes object read from a manifest.
+type resource struct {
+	apiVersion string
+	kind       string
+	name       string
+	object     *node
+
+	// id is the construct ID, set before the construct is generated.
+	id string
+
+	// variable is the variable the construct is assigned to, set before the
+	// construct is generated. It is only declared when declare is set.
+	variable string
+	declare  bool
+
+	// params are the params of the chart the construct reads, set before
+	// the construct is generated.
+	params []Param
+
+	// scope is where the class and structs of the object's construct are
+	// declared, set before the construct is generated.
+	scope *scope
+
+	// plus is the cdk8s-plus construct of the object, nil unless cdk8s-plus
+	// is targeted and has a class for it.
+	plus *plusObject
+
+	// comments are the comments of the document and of the top-level
+	// apiVersion and kind, written above the construct.
+	comments []string
+}
+
+// newResource validates that n is a kubernetes object and extracts its
+// identifying fields.
+func newResource(n *node) (*resource, error) {
+	if n.kind != mapNode {
+		return nil, fmt.Errorf("manifest is not a kubernetes object")
+	}
+
+	r := &resource{
+		apiVersion: n.str("apiVersion"),
+		kind:       n.str("kind"),
+		name:       n.get("metadata").str("name"),
+		object:     n,
+	}
+
+	if r.kind == "" {
+		return nil, fmt.Errorf("manifest has no kind")
+	}
+	dropNulls(n)
+
+	for _, key := range []string{"apiVersion", "kind"} {
+		if v := n.get(key); v != nil {
+			r.comments = append(r.comments, v.comments...)
+		}
+	}
+
+	return r, nil
+}
+
+// dropNulls removes the null fields of the maps in n, such as the
+// creationTimestamp: null kubectl writes, since kubernetes reads them as
+// unset. Null list items are kept.
+func dropNulls(n *node) {
+	switch n.kind {
+	case mapNode:
+		fields := n.fields[:0]
+		for _, f := range n.fields {
+			if f.value.kind != nullNode {
+				dropNulls(f.value)
+				fields = append(fields, f)
+			}
+		}
+		n.fields = fields
+
+	case listNode:
+		for _, item := range n.items {
+			dropNulls(item)
+		}
+	}
+}
+
+// props returns the object without its top-level apiVersion and kind, which
+// cdk8s sets from the construct class, and without the status the cluster
+// writes.
+func (r *resource) props() *node {
+	props := &node{kind: mapNode}
+	for _, f := range r.object.fields {
+		if f.key == "apiVersion" || f.key == "kind" || f.key == "status" {
+			continue
+		}
+		props.fields = append(props.fields, f)
+	}
+
+	return props
+}
+
+// parseResource reads input as a single kubernetes object. Input holding
+// more than one document is rejected rather than silently truncated.
+func parseResource(input []byte) (*resource, error) {
+	docs, _, err := decodeDocuments(input)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(docs) {
+	case 0:
+		return nil, fmt.Errorf("no kubernetes object found")
+	case 1:
+		return parseDocument(docs[0].node)
+	}
+
+	return nil, fmt.Errorf("found %d documents, convert them with Kube2CDK8SMultiple or Convert", len(docs))
+}
+
+// decodeDocuments decodes the YAML document stream in input. Only --- at the
+// start of a line separates documents and ... ends one, so separators inside
+// block scalars such as PEM certificates are left alone. Empty and
+// comment-only documents are dropped, after being counted so that the index
+// of every document is its position in the stream. Documents decoded before
+// a syntax error are returned along with the error and the position of the
+// document that failed.
+func decodeDocuments(input []byte) ([]document, int, error) {
+	var docs []document
+
+	dec := yaml.NewDecoder(bytes.NewReader(dropLeadingComments(input)))
+	for index := 1; ; index++ {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			return docs, index, nil
+		}
+		if err != nil {
+			return docs, index, err
+		}
+
+		if len(doc.Content) == 0 || doc.Content[0].ShortTag() == "!!null" {
+			continue
+		}
+
+		docs = append(docs, document{index: index, node: &doc})
+	}
+}
+
+// dropLeadingComments returns input with the comments written before its
+// first --- blanked out. They belong to no document, while the decoder would
+// hand them to the first key of the document after the ---.
+func dropLeadingComments(input []byte) []byte {
+	lines := bytes.SplitAfter(input, []byte("\n"))
+	comments := false
+	for i, line := range lines {
+		text := strings.TrimSpace(string(line))
+		switch {
+		case text == "":
+			continue
+		case strings.HasPrefix(text, "#"):
+			comments = true
+			continue
+		case comments && (text == "---" || strings.HasPrefix(string(line), "--- ")):
+			// blank lines keep the line numbers of errors
+			return append(bytes.Repeat([]byte("\n"), i), bytes.Join(lines[i:], nil)...)
+		}
+
+		return input
+	}
+
+	return input
+}
+
+// parseDocument reads a decoded YAML document as a kubernetes object.
+func parseDocument(doc *yaml.Node) (*resource, error) {
+	n, err := newNode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := newResource(n)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := commentLines(doc.HeadComment)
+	if len(doc.Content) > 0 {
+		comments = append(comments, commentLines(doc.Content[0].HeadComment, doc.Content[0].LineComment)...)
+	}
+	r.comments = append(comments, r.comments...)
+
+	return r, nil
+}
+
+// document is a decoded YAML document and where it was read from.
+type document struct {
+	source string
+	index  int
+	node   *yaml.Node
+}
+
+// decodeSource decodes the YAML document stream of a file or reader named
+// source. When the stream has a syntax error, the documents before it are
+// returned with the error of the document that failed.
+func decodeSource(source string, input []byte) ([]document, *DocumentError) {
+	docs, index, err := decodeDocuments(input)
+	for i := range docs {
+		docs[i].source = source
+	}
+
+	if err != nil {
+		return docs, &DocumentError{Source: source, Document: index, Err: err}
+	}
+
+	return docs, nil
+}
@@ -0,0 +1,317 @@
+package kube2cdk8s
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// APIObject is the Module alias that generates the resources of an API group
+// as plain cdk8s ApiObjects instead of classes imported with cdk8s import.
+const APIObject = "ApiObject"
+
+// Module is the import of the bindings cdk8s import generates for the custom
+// resources of an API group.
+type Module struct {
+	// Alias is the name the module is imported as and its classes are
+	// qualified with, or APIObject.
+	Alias string
+
+	// Path is the module imported. If empty, it is the module cdk8s import
+	// writes next to the k8s bindings.
+	Path string
+}
+
+// builtinKinds lists the kinds of each API group that are part of the k8s
+// bindings. Other kinds of these groups have no class to generate.
+var builtinKinds = map[string][]string{
+	"":                             {"Binding", "ComponentStatus", "ConfigMap", "Endpoints", "Event", "LimitRange", "Namespace", "Node", "PersistentVolume", "PersistentVolumeClaim", "Pod", "PodTemplate", "ReplicationController", "ResourceQuota", "Secret", "Service", "ServiceAccount"},
+	"admissionregistration.k8s.io": {"MutatingAdmissionPolicy", "MutatingAdmissionPolicyBinding", "MutatingWebhookConfiguration", "ValidatingAdmissionPolicy", "ValidatingAdmissionPolicyBinding", "ValidatingWebhookConfiguration"},
+	"apiextensions.k8s.io":         {"CustomResourceDefinition"},
+	"apiregistration.k8s.io":       {"APIService"},
+	"apps":                         {"ControllerRevision", "DaemonSet", "Deployment", "ReplicaSet", "StatefulSet"},
+	"authentication.k8s.io":        {"SelfSubjectReview", "TokenRequest", "TokenReview"},
+	"authorization.k8s.io":         {"LocalSubjectAccessReview", "SelfSubjectAccessReview", "SelfSubjectRulesReview", "SubjectAccessReview"},
+	"autoscaling":                  {"HorizontalPodAutoscaler", "Scale"},
+	"batch":                        {"CronJob", "Job"},
+	"certificates.k8s.io":          {"CertificateSigningRequest", "ClusterTrustBundle"},
+	"coordination.k8s.io":          {"Lease", "LeaseCandidate"},
+	"discovery.k8s.io":             {"EndpointSlice"},
+	"events.k8s.io":                {"Event"},
+	"extensions":                   {"DaemonSet", "Deployment", "Ingress", "NetworkPolicy", "PodSecurityPolicy", "ReplicaSet"},
+	"flowcontrol.apiserver.k8s.io": {"FlowSchema", "PriorityLevelConfiguration"},
+	"internal.apiserver.k8s.io":    {"StorageVersion"},
+	"networking.k8s.io":            {"IPAddress", "Ingress", "IngressClass", "NetworkPolicy", "ServiceCIDR"},
+	"node.k8s.io":                  {"RuntimeClass"},
+	"policy":                       {"Eviction", "PodDisruptionBudget", "PodSecurityPolicy"},
+	"rbac.authorization.k8s.io":    {"ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding"},
+	"resource.k8s.io":              {"DeviceClass", "PodSchedulingContext", "ResourceClaim", "ResourceClaimTemplate", "ResourceSlice"},
+	"scheduling.k8s.io":            {"PriorityClass"},
+	"storage.k8s.io":               {"CSIDriver", "CSINode", "CSIStorageCapacity", "StorageClass", "VolumeAttachment", "VolumeAttributesClass"},
+	"storagemigration.k8s.io":      {"StorageVersionMigration"},
+}
+
+// apiGroup returns the API group of apiVersion, "" for the core group.
+func apiGroup(apiVersion string) string {
+	if i := strings.LastIndex(apiVersion, "/"); i >= 0 {
+		return apiVersion[:i]
+	}
+
+	return ""
+}
+
+// validate checks the import aliases, the Kubernetes version, the target and
+// the ID strategy set in opts.
+func (opts Options) validate() error {
+	if _, err := opts.importAlias(); err != nil {
+		return err
+	}
+
+	if _, err := opts.kubernetesMinor(); err != nil {
+		return err
+	}
+
+	if _, err := opts.idTemplate(); err != nil {
+		return err
+	}
+
+	switch opts.Target {
+	case "", TargetK8s:
+	case TargetPlus:
+		if opts.Language != "" && opts.Language != TypeScript {
+			return fmt.Errorf("cdk8s-plus output is only supported in %s", TypeScript)
+		}
+		if minor, _ := opts.kubernetesMinor(); minor != 0 && minor < minPlusMinor {
+			return fmt.Errorf("cdk8s-plus requires kubernetes 1.%d or later, targeting %s", minPlusMinor, opts.KubernetesVersion)
+		}
+	default:
+		return fmt.Errorf("unsupported target: %s", opts.Target)
+	}
+
+	if len(opts.Params) > 0 {
+		if opts.Language != "" && opts.Language != TypeScript {
+			return fmt.Errorf("params are only supported in %s", TypeScript)
+		}
+		if opts.Target == TargetPlus {
+			return fmt.Errorf("params cannot be used with the %s target", TargetPlus)
+		}
+		if _, err := opts.paramSelectors(); err != nil {
+			return err
+		}
+	}
+
+	alias, _ := opts.importAlias()
+	groups := map[string]string{}
+	for group, m := range opts.Modules {
+		if m.Alias == "" || m.Alias == APIObject {
+			continue
+		}
+		if !importAliasPattern.MatchString(m.Alias) {
+			return fmt.Errorf("invalid import alias %q for %s", m.Alias, group)
+		}
+		if m.Alias == alias || contains(reservedAliases, m.Alias) {
+			return fmt.Errorf("import alias %q of %s is already imported by the generated code", m.Alias, group)
+		}
+		if other, ok := groups[m.Alias]; ok {
+			first, second := other, group
+			if second < first {
+				first, second = second, first
+			}
+			return fmt.Errorf("import alias %q is set for both %s and %s", m.Alias, first, second)
+		}
+		groups[m.Alias] = group
+	}
+
+	return nil
+}
+
+// scope returns the scope the construct of r is generated in, and the module
+// it is imported from, empty for an ApiObject. Builtin resources holding
+// fields of unknownType are generated as an ApiObject.
+func (opts Options) scope(r *resource, language Language) (*scope, Module) {
+	alias, _ := opts.importAlias()
+	k8s := Module{Alias: alias, Path: opts.importPath(language)}
+
+	s := &scope{kind: r.kind, module: alias, cdk8s: map[string]bool{}}
+
+	group := apiGroup(r.apiVersion)
+	kinds, builtin := builtinKinds[group]
+
+	m := opts.Modules[group]
+	if m.Alias == APIObject || opts.isAPIObject(group, r.kind) || (builtin && !contains(kinds, r.kind)) {
+		s.apiObject = true
+		s.module = cdk8sModule
+		return s, Module{}
+	}
+
+	if builtin {
+		s.group = group
+		s.suffix = versionSuffix(apiVersionOf(r.apiVersion))
+		if unknown := s.unknownFields(s.propsType(), "", r.props()); len(unknown) > 0 {
+			return &scope{kind: r.kind, module: cdk8sModule, apiObject: true, cdk8s: map[string]bool{}, unknown: unknown}, Module{}
+		}
+		return s, k8s
+	}
+
+	if m.Alias == "" {
+		m.Alias = moduleAlias(group)
+	}
+	if m.Path == "" {
+		m.Path = modulePath(language, k8s.Path, group)
+	}
+
+	s.custom = true
+	s.module = m.Alias
+
+	return s, m
+}
+
+// isAPIObject reports whether opts.APIObjects lists kind, alone or in group.
+func (opts Options) isAPIObject(group, kind string) bool {
+	for _, k := range opts.APIObjects {
+		if k == kind || k == group+"/"+kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// moduleAlias returns the alias of the module of group, its first label
+// without punctuation, e.g. certmanager for cert-manager.io.
+func moduleAlias(group string) string {
+	return labelsAlias(group, 1)
+}
+
+// labelsAlias returns the first n labels of group joined without
+// punctuation, e.g. networkingistio for networking.istio.io and 2.
+func labelsAlias(group string, n int) string {
+	labels := strings.Split(group, ".")
+	if n < len(labels) {
+		labels = labels[:n]
+	}
+
+	var alias strings.Builder
+	for _, r := range strings.ToLower(strings.Join(labels, "")) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			alias.WriteRune(r)
+		}
+	}
+
+	if alias.Len() == 0 || unicode.IsDigit(rune(alias.String()[0])) {
+		return "crd" + alias.String()
+	}
+
+	return alias.String()
+}
+
+// reservedAliases are the names the generated code imports besides the k8s
+// bindings, which the module of a group cannot be imported as.
+var reservedAliases = []string{cdk8sModule, plusAlias, "constructs", "jsii"}
+
+// withModuleAliases returns opts with an alias in Modules for the group of
+// every custom resource of resources that has none. A group gets its first
+// label, or as many labels as it takes to tell it apart from the other
+// groups, the k8s bindings and the aliases already set, e.g.
+// networkingistio and networkinggke for networking.istio.io and
+// networking.gke.io, and k8snginx for k8s.nginx.org.
+func (opts Options) withModuleAliases(resources []*resource) (Options, error) {
+	alias, _ := opts.importAlias()
+	taken := map[string]string{alias: "the k8s bindings"}
+	for _, name := range reservedAliases {
+		taken[name] = name
+	}
+
+	modules := map[string]Module{}
+	for group, m := range opts.Modules {
+		modules[group] = m
+		if m.Alias != "" && m.Alias != APIObject {
+			taken[m.Alias] = group
+		}
+	}
+
+	var groups []string
+	seen := map[string]bool{}
+	for _, r := range resources {
+		if r == nil {
+			continue
+		}
+		group := apiGroup(r.apiVersion)
+		if _, builtin := builtinKinds[group]; builtin || seen[group] || modules[group].Alias != "" || opts.isAPIObject(group, r.kind) {
+			continue
+		}
+		seen[group] = true
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	for n := 1; len(groups) > 0; n++ {
+		aliases := map[string][]string{}
+		for _, group := range groups {
+			aliases[labelsAlias(group, n)] = append(aliases[labelsAlias(group, n)], group)
+		}
+
+		var left []string
+		for _, group := range groups {
+			a := labelsAlias(group, n)
+			if _, ok := taken[a]; ok || len(aliases[a]) > 1 {
+				if n >= strings.Count(group, ".")+1 {
+					other := taken[a]
+					if other == "" || other == group {
+						other = strings.Join(aliases[a], " and ")
+					}
+					return opts, fmt.Errorf("the module of %s cannot be told apart from %s as %s, set its alias with Modules", group, other, a)
+				}
+				left = append(left, group)
+				continue
+			}
+
+			m := modules[group]
+			m.Alias = a
+			modules[group] = m
+		}
+		for _, group := range groups {
+			if m := modules[group]; m.Alias != "" {
+				taken[m.Alias] = group
+			}
+		}
+		groups = left
+	}
+
+	opts.Modules = modules
+	return opts, nil
+}
+
+// modulePath returns the module cdk8s import writes the bindings of group to,
+// next to the k8s bindings imported from k8sPath.
+func modulePath(language Language, k8sPath, group string) string {
+	words := strings.FieldsFunc(strings.ToLower(group), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	switch language {
+	case Python, Java:
+		return k8sPath[:strings.LastIndex(k8sPath, ".")+1] + strings.Join(words, "_")
+	case CSharp:
+		for i, w := range words {
+			words[i] = upperFirst(w)
+		}
+		return k8sPath[:strings.LastIndex(k8sPath, ".")+1] + strings.Join(words, "")
+	case Go:
+		return k8sPath[:strings.LastIndex(k8sPath, "/")+1] + strings.Join(words, "")
+	}
+
+	return k8sPath[:strings.LastIndex(k8sPath, "/")+1] + group
+}
+
+// sortedKeys returns the keys of set in order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,254 @@
+package kube2cdk8s
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bradleyjkemp/cupaloy"
+)
+
+const customResources = `
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: web-tls
+spec:
+  secretName: web-tls
+  dnsNames:
+  - example.com
+  issuerRef:
+    name: letsencrypt
+    kind: ClusterIssuer
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: my-widget
+spec:
+  size: 3
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: my-service-account
+`
+
+func TestCustomResourceChart(t *testing.T) {
+	for _, language := range []Language{TypeScript, Python, Go, Java, CSharp} {
+		opts := Options{
+			Language: language,
+			Modules:  map[string]Module{"example.com": {Alias: APIObject}},
+		}
+
+		constructs, err := Convert(context.Background(), strings.NewReader(customResources), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		chart, err := Chart("", constructs, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = cupaloy.SnapshotMulti(string(language), chart)
+		if err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
+
+func TestCustomResourceModule(t *testing.T) {
+	opts := Options{
+		Modules: map[string]Module{
+			"cert-manager.io": {Alias: "cm", Path: "../imports/cert-manager"},
+		},
+	}
+
+	constructs, err := Convert(context.Background(), strings.NewReader(customResources), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chart, err := Chart("", constructs, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"import * as cm from '../imports/cert-manager';",
+		"new cm.Certificate(this, \"web-tls\", {",
+		"import * as example from './imports/example.com';",
+		"new example.Widget(this, \"my-widget\", {",
+	} {
+		if !strings.Contains(chart, want) {
+			t.Errorf("expected %q in\n%s", want, chart)
+		}
+	}
+}
+
+func TestInvalidModuleAlias(t *testing.T) {
+	opts := Options{Modules: map[string]Module{"cert-manager.io": {Alias: "cert-manager"}}}
+
+	if _, err := Convert(context.Background(), strings.NewReader(customResources), opts); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestModuleNames(t *testing.T) {
+	tests := []struct {
+		group string
+		alias string
+		paths map[Language]string
+	}{
+		{"cert-manager.io", "certmanager", map[Language]string{
+			TypeScript: "./imports/cert-manager.io",
+			Python:     "imports.cert_manager_io",
+			Go:         "example.com/app/imports/certmanagerio",
+			Java:       "imports.cert_manager_io",
+			CSharp:     "Imports.CertManagerIo",
+		}},
+		{"monitoring.coreos.com", "monitoring", map[Language]string{
+			TypeScript: "./imports/monitoring.coreos.com",
+			Python:     "imports.monitoring_coreos_com",
+		}},
+		{"3scale.net", "crd3scale", nil},
+	}
+
+	for _, tt := range tests {
+		if alias := moduleAlias(tt.group); alias != tt.alias {
+			t.Errorf("%s: got alias %s, want %s", tt.group, alias, tt.alias)
+		}
+		for language, want := range tt.paths {
+			if got := modulePath(language, defaultImportPaths[language], tt.group); got != want {
+				t.Errorf("%s in %s: got path %s, want %s", tt.group, language, got, want)
+			}
+		}
+	}
+}
+
+const unknownKinds = `
+apiVersion: v1
+kind: PodPreset
+metadata:
+  name: inject-env
+spec:
+  env:
+  - name: LOG_LEVEL
+    value: debug
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 2
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web
+data:
+  key: value
+`
+
+func TestAPIObjectFallback(t *testing.T) {
+	for _, language := range []Language{TypeScript, Python, Go, Java, CSharp} {
+		opts := Options{Language: language, APIObjects: []string{"apps/Deployment"}}
+
+		constructs, err := Convert(context.Background(), strings.NewReader(unknownKinds), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = cupaloy.SnapshotMulti(string(language), Join(constructs, language))
+		if err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
+
+func TestAPIObjectKinds(t *testing.T) {
+	tests := []struct {
+		kinds []string
+		want  []bool
+	}{
+		{nil, []bool{true, false, false}},
+		{[]string{"Deployment"}, []bool{true, true, false}},
+		{[]string{"apps/Deployment", "ConfigMap"}, []bool{true, true, true}},
+		{[]string{"batch/Deployment"}, []bool{true, false, false}},
+	}
+
+	for _, tt := range tests {
+		constructs, err := Convert(context.Background(), strings.NewReader(unknownKinds), Options{APIObjects: tt.kinds})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, c := range constructs {
+			got := strings.HasPrefix(c.Code, "new ApiObject(")
+			if got != tt.want[i] {
+				t.Errorf("%v: %s generated as ApiObject: %t, want %t", tt.kinds, c.Kind, got, tt.want[i])
+			}
+		}
+	}
+}
+
+const sharedLabelResources = `
+apiVersion: networking.istio.io/v1beta1
+kind: Gateway
+metadata:
+  name: public
+---
+apiVersion: networking.gke.io/v1
+kind: ManagedCertificate
+metadata:
+  name: public
+---
+apiVersion: k8s.nginx.org/v1
+kind: VirtualServer
+metadata:
+  name: public
+`
+
+func TestModuleAliasCollisions(t *testing.T) {
+	constructs, err := Convert(context.Background(), strings.NewReader(sharedLabelResources), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var aliases []string
+	for _, c := range constructs {
+		aliases = append(aliases, c.module.Alias)
+	}
+	want := []string{"networkingistio", "networkinggke", "k8snginx"}
+	if !reflect.DeepEqual(aliases, want) {
+		t.Errorf("got aliases %v, want %v", aliases, want)
+	}
+
+	chart, err := Chart("", constructs, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cupaloy.Snapshot(chart)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	tests := []struct {
+		modules map[string]Module
+		err     string
+	}{
+		{map[string]Module{"networking.istio.io": {Alias: "istio"}, "networking.gke.io": {Alias: "istio"}}, `import alias "istio" is set for both networking.gke.io and networking.istio.io`},
+		{map[string]Module{"k8s.nginx.org": {Alias: "k8s"}}, `import alias "k8s" of k8s.nginx.org is already imported`},
+	}
+
+	for _, tt := range tests {
+		_, err := Convert(context.Background(), strings.NewReader(sharedLabelResources), Options{Modules: tt.modules})
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%v: got %v, want an error containing %q", tt.modules, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,443 @@
+package kube2cdk8s
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Param is a value lifted out of the objects into a prop of the chart,
+// which the constructs read instead of the value.
+type Param struct {
+	// Name is the prop, e.g. replicas.
+	Name string
+
+	// Type is the TypeScript type of the prop, e.g. number.
+	Type string
+
+	// Default is the original value as a TypeScript literal.
+	Default string
+}
+
+// paramShorthands are the selectors of the params that can be given by
+// name alone in Options.Params.
+var paramShorthands = map[string][]string{
+	"namespace": {"metadata.namespace"},
+	"replicas":  {"spec.replicas"},
+	"image":     {"..containers[*].image", "..initContainers[*].image"},
+	"tag":       {"..containers[*].image", "..initContainers[*].image"},
+	"limits":    {"..containers[*].resources.limits"},
+	"requests":  {"..containers[*].resources.requests"},
+}
+
+// tagParam lifts the tag of the images it selects rather than the whole
+// image.
+const tagParam = "tag"
+
+// paramSelector selects the values lifted into the prop called name, or
+// only their image tag when tag is set.
+type paramSelector struct {
+	name  string
+	paths [][]pathStep
+	tag   bool
+}
+
+// pathStep is a single step of a selector path: a key, recursive when
+// written ..key, an item of a list or a wildcard.
+type pathStep struct {
+	key       string
+	all       bool
+	recursive bool
+
+	// item is the position of the item selected in a list plus one, zero
+	// unless the step selects an item.
+	item int
+}
+
+// paramSelectors parses the params set in opts, each either a shorthand or
+// name=selector.
+func (opts Options) paramSelectors() ([]paramSelector, error) {
+	var selectors []paramSelector
+	names := map[string]bool{}
+
+	for _, param := range opts.Params {
+		name, selector, hasSelector := param, "", false
+		if i := strings.Index(param, "="); i >= 0 {
+			name, selector, hasSelector = strings.TrimSpace(param[:i]), strings.TrimSpace(param[i+1:]), true
+		}
+
+		if !tsIdentifier.MatchString(name) {
+			return nil, fmt.Errorf("invalid param name %q", name)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("param %s is given more than once", name)
+		}
+		names[name] = true
+
+		sources := []string{selector}
+		if !hasSelector {
+			var ok bool
+			sources, ok = paramShorthands[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown param %q, expected one of %s or name=selector", name, strings.Join(sortedKeys(shorthandNames()), ", "))
+			}
+		}
+
+		s := paramSelector{name: name, tag: name == tagParam && !hasSelector}
+		for _, source := range sources {
+			path, err := parsePath(source)
+			if err != nil {
+				return nil, fmt.Errorf("param %s: %w", name, err)
+			}
+			s.paths = append(s.paths, path)
+		}
+		selectors = append(selectors, s)
+	}
+
+	return selectors, nil
+}
+
+// shorthandNames returns the set of the params that have a shorthand.
+func shorthandNames() map[string]bool {
+	names := map[string]bool{}
+	for name := range paramShorthands {
+		names[name] = true
+	}
+
+	return names
+}
+
+// parsePath parses a JSONPath-like selector such as
+// $.spec.template.spec.containers[0].image, where ..key matches key at any
+// depth, [*] any item and ['key'] a key holding dots.
+func parsePath(selector string) ([]pathStep, error) {
+	rest := strings.TrimPrefix(selector, "$")
+	if rest != "" && rest[0] != '.' && rest[0] != '[' {
+		rest = "." + rest
+	}
+
+	var path []pathStep
+	for rest != "" {
+		var step pathStep
+		switch {
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid selector %q: missing ]", selector)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+
+			switch {
+			case inner == "*":
+				step.all = true
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				step.key = inner[1 : len(inner)-1]
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("invalid selector %q: bad index [%s]", selector, inner)
+				}
+				step.item = index + 1
+			}
+
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			if strings.HasPrefix(rest, ".") {
+				step.recursive = true
+				rest = rest[1:]
+			}
+
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			step.key = rest[:end]
+			rest = rest[end:]
+
+			if step.key == "*" {
+				step.key, step.all = "", true
+			}
+			if step.key == "" && !step.all {
+				return nil, fmt.Errorf("invalid selector %q: empty key", selector)
+			}
+
+		default:
+			return nil, fmt.Errorf("invalid selector %q", selector)
+		}
+
+		path = append(path, step)
+	}
+
+	if len(path) == 0 {
+		return nil, errors.New("empty selector")
+	}
+
+	return path, nil
+}
+
+// selectValues calls visit with the slot of every value below *slot that
+// path selects and its type in scope sc, so that visit can replace it. The
+// value of *slot is of type t.
+func selectValues(sc *scope, slot **node, t typeRef, path []pathStep, visit func(slot **node, t typeRef)) {
+	if len(path) == 0 {
+		visit(slot, t)
+		return
+	}
+
+	n, step := *slot, path[0]
+	if step.recursive {
+		here := step
+		here.recursive = false
+		selectValues(sc, slot, t, append([]pathStep{here}, path[1:]...), visit)
+		n.eachChild(sc, t, func(child **node, t typeRef) {
+			selectValues(sc, child, t, path, visit)
+		})
+		return
+	}
+
+	switch {
+	case step.all:
+		n.eachChild(sc, t, func(child **node, t typeRef) {
+			selectValues(sc, child, t, path[1:], visit)
+		})
+	case step.item > 0:
+		if n.kind == listNode && step.item <= len(n.items) {
+			selectValues(sc, &n.items[step.item-1], sc.itemType(t), path[1:], visit)
+		}
+	case n.kind == mapNode:
+		for _, f := range n.fields {
+			if f.key == step.key {
+				selectValues(sc, &f.value, sc.valueType(t, f), path[1:], visit)
+			}
+		}
+	}
+}
+
+// eachChild calls fn with the slot and the type of every value of a map or
+// item of a list of type t.
+func (n *node) eachChild(sc *scope, t typeRef, fn func(child **node, t typeRef)) {
+	for _, f := range n.fields {
+		fn(&f.value, sc.valueType(t, f))
+	}
+	for i := range n.items {
+		fn(&n.items[i], sc.itemType(t))
+	}
+}
+
+// valueType returns the type of the value of f in a map of type t.
+func (s *scope) valueType(t typeRef, f *field) typeRef {
+	switch t.kind {
+	case structType:
+		return s.fieldType(t.name, f.key, f.value)
+	case mapType:
+		return *t.elem
+	}
+
+	return typeRef{kind: inferredType}
+}
+
+// itemType returns the type of the items of a list of type t.
+func (s *scope) itemType(t typeRef) typeRef {
+	if t.kind == listType {
+		return *t.elem
+	}
+
+	return typeRef{kind: inferredType}
+}
+
+// liftParams replaces the values the params of opts select with the props
+// of the chart, and records in every resource the params it reads. A value
+// differing from the one an earlier object gave the param is lifted into a
+// numbered param, e.g. replicas2.
+func (opts Options) liftParams(resources []*resource) error {
+	selectors, err := opts.paramSelectors()
+	if err != nil {
+		return err
+	}
+
+	var params []Param
+	for _, r := range resources {
+		if r == nil {
+			continue
+		}
+
+		// the props are typed like the fields they are lifted from
+		sc, _ := opts.scope(r, TypeScript)
+		root := typeRef{kind: structType, name: sc.propsType()}
+		if sc.apiObject {
+			root = typeRef{kind: inferredType}
+		}
+
+		for _, s := range selectors {
+			for _, path := range s.paths {
+				selectValues(sc, &r.object, root, path, func(slot **node, t typeRef) {
+					n := *slot
+
+					value, repository := n, ""
+					if s.tag {
+						var tag string
+						var ok bool
+						if repository, tag, ok = splitTag(n); !ok {
+							return
+						}
+						value = &node{kind: stringNode, value: tag}
+					} else if n.kind == nullNode || n.kind == codeNode || n.kind == callNode {
+						return
+					}
+
+					if s.tag {
+						t = typeRef{kind: inferredType}
+					}
+					param := Param{Name: s.name, Type: tsTypeOf(sc, t, value), Default: tsLiteral(sc, t, value)}
+					params, param.Name = addParam(params, param)
+					r.addParam(param)
+
+					code := "props." + param.Name
+					if s.tag {
+						code = strings.TrimSuffix(tsTemplate(repository+":"), "`") + "${" + code + "}`"
+					}
+					*slot = &node{kind: codeNode, value: code, comments: n.comments}
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+// splitTag splits the image held by n into its repository and tag. Images
+// without a tag or pinned by digest are not split.
+func splitTag(n *node) (repository, tag string, ok bool) {
+	if n.kind != stringNode || strings.Contains(n.value, "@") {
+		return "", "", false
+	}
+
+	i := strings.LastIndex(n.value, ":")
+	if i < 0 || i < strings.LastIndex(n.value, "/") {
+		return "", "", false
+	}
+
+	return n.value[:i], n.value[i+1:], true
+}
+
+// addParam adds p to params unless a param of the same name has the same
+// default, numbering its name while another default holds it. It returns
+// the name p is lifted into.
+func addParam(params []Param, p Param) ([]Param, string) {
+	name := p.Name
+	for i := 2; ; i++ {
+		existing, ok := findParam(params, p.Name)
+		if !ok {
+			return append(params, p), p.Name
+		}
+		if existing == p {
+			return params, p.Name
+		}
+		p.Name = name + strconv.Itoa(i)
+	}
+}
+
+// findParam returns the param of params called name.
+func findParam(params []Param, name string) (Param, bool) {
+	for _, p := range params {
+		if p.Name == name {
+			return p, true
+		}
+	}
+
+	return Param{}, false
+}
+
+// addParam records that the construct of r reads p.
+func (r *resource) addParam(p Param) {
+	if _, ok := findParam(r.params, p.Name); !ok {
+		r.params = append(r.params, p)
+	}
+}
+
+// tsType returns the TypeScript type of n. Maps whose values share a type
+// are typed as an index signature, so that keys can be added.
+func tsType(n *node) string {
+	return tsTypeOf(nil, typeRef{kind: inferredType}, n)
+}
+
+// tsTypeOf returns the TypeScript type of n, a value of type t in scope s:
+// the struct of the bindings or the union class of a Quantity or an
+// IntOrString, and the type of its value otherwise, see tsType.
+func tsTypeOf(s *scope, t typeRef, n *node) string {
+	if t.kind == structType && t.module != cdk8sModule && n.kind == mapNode {
+		return s.module + "." + t.name
+	}
+
+	switch n.kind {
+	case stringNode, intNode, floatNode:
+		switch t.kind {
+		case quantityType:
+			return s.module + ".Quantity"
+		case intOrStringType:
+			return s.module + ".IntOrString"
+		}
+	}
+
+	switch n.kind {
+	case stringNode:
+		return "string"
+	case intNode, floatNode:
+		return "number"
+	case boolNode:
+		return "boolean"
+
+	case listNode:
+		types := make([]string, len(n.items))
+		for i, item := range n.items {
+			types[i] = tsTypeOf(s, s.itemType(t), item)
+		}
+		if t := commonType(types); t != "" {
+			return t + "[]"
+		}
+		return "any[]"
+
+	case mapNode:
+		types := make([]string, len(n.fields))
+		for i, f := range n.fields {
+			types[i] = tsTypeOf(s, s.valueType(t, f), f.value)
+		}
+		if t := commonType(types); t != "" {
+			return "{ [key: string]: " + t + " }"
+		}
+
+		fields := make([]string, len(n.fields))
+		for i, f := range n.fields {
+			fields[i] = tsKey(f.key) + ": " + types[i]
+		}
+		return "{ " + strings.Join(fields, "; ") + " }"
+	}
+
+	return "any"
+}
+
+// commonType returns the type every type of types is, empty if they are none
+// or differ.
+func commonType(types []string) string {
+	if len(types) == 0 {
+		return ""
+	}
+
+	for _, t := range types[1:] {
+		if t != types[0] {
+			return ""
+		}
+	}
+
+	return types[0]
+}
+
+// tsLiteral returns n, a value of type t in scope s, as a TypeScript literal.
+func tsLiteral(s *scope, t typeRef, n *node) string {
+	var b strings.Builder
+	tsTyped(&b, s, t, n, 0)
+
+	return b.String()
+}
@@ -0,0 +1,309 @@
+package kube2cdk8s
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bradleyjkemp/cupaloy"
+)
+
+const paramResources = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: prod
+  annotations:
+    example.com/owner: payments
+spec:
+  replicas: 3
+  selector:
+    matchLabels:
+      app: api
+  template:
+    metadata:
+      labels:
+        app: api
+    spec:
+      initContainers:
+      - name: migrate
+        image: registry.example.com:5000/api-migrations:1.4.0
+      containers:
+      - name: api
+        # pinned by the release pipeline
+        image: registry.example.com:5000/api:1.4.0
+        resources:
+          limits:
+            cpu: 500m
+            memory: 256Mi
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+  namespace: prod
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: worker
+  template:
+    metadata:
+      labels:
+        app: worker
+    spec:
+      containers:
+      - name: worker
+        image: registry.example.com:5000/api:1.4.0
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+  namespace: prod
+spec:
+  selector:
+    app: api
+  ports:
+  - port: 80
+`
+
+func TestParamsChart(t *testing.T) {
+	opts := Options{Params: []string{
+		"namespace",
+		"replicas",
+		"tag",
+		"limits",
+		"owner=metadata.annotations['example.com/owner']",
+		"servicePort=$.spec.ports[0].port",
+	}}
+
+	constructs, err := Convert(context.Background(), strings.NewReader(paramResources), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names [][]string
+	for _, c := range constructs {
+		var construct []string
+		for _, p := range c.Params() {
+			construct = append(construct, p.Name)
+		}
+		names = append(names, construct)
+	}
+	want := [][]string{
+		{"namespace", "replicas", "tag", "limits", "owner"},
+		{"namespace", "replicas2", "tag"},
+		{"namespace", "servicePort"},
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("got params %v, want %v", names, want)
+	}
+
+	chart, err := Chart("api", constructs, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cupaloy.Snapshot(chart)
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestParamsImage(t *testing.T) {
+	constructs, err := Convert(context.Background(), strings.NewReader(paramResources), Options{Params: []string{"image"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Param{{Name: "image", Type: "string", Default: `"registry.example.com:5000/api:1.4.0"`}, {Name: "image2", Type: "string", Default: `"registry.example.com:5000/api-migrations:1.4.0"`}}
+	if got := constructs[0].Params(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got params %v, want %v", got, want)
+	}
+	if got := constructs[1].Params(); !reflect.DeepEqual(got, want[:1]) {
+		t.Errorf("got params %v, want %v", got, want[:1])
+	}
+	if !strings.Contains(constructs[1].Code, "image: props.image,") {
+		t.Errorf("image is not read from props:\n%s", constructs[1].Code)
+	}
+	if constructs[2].Params() != nil {
+		t.Errorf("got params %v for a Service", constructs[2].Params())
+	}
+}
+
+func TestParamUnionTypes(t *testing.T) {
+	manifests := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  template:
+    spec:
+      containers:
+      - name: api
+        image: api:1.4.0
+        resources:
+          requests:
+            cpu: 0.5
+            memory: 256Mi
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+spec:
+  ports:
+  - port: 80
+    targetPort: http
+`
+
+	opts := Options{Params: []string{"requests", "targetPort=$.spec.ports[0].targetPort", "servicePort=$.spec.ports[0].port"}}
+	constructs, err := Convert(context.Background(), strings.NewReader(manifests), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Param{{Name: "requests", Type: "{ [key: string]: k8s.Quantity }", Default: `{
+    cpu: k8s.Quantity.fromNumber(0.5),
+    memory: k8s.Quantity.fromString("256Mi"),
+}`}}
+	if got := constructs[0].Params(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got params %v, want %v", got, want)
+	}
+
+	want = []Param{
+		{Name: "targetPort", Type: "k8s.IntOrString", Default: `k8s.IntOrString.fromString("http")`},
+		{Name: "servicePort", Type: "number", Default: "80"},
+	}
+	if got := constructs[1].Params(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got params %v, want %v", got, want)
+	}
+	if !strings.Contains(constructs[1].Code, "targetPort: props.targetPort,") {
+		t.Errorf("targetPort is not read from props:\n%s", constructs[1].Code)
+	}
+}
+
+func TestInvalidParams(t *testing.T) {
+	tests := []struct {
+		opts Options
+		err  string
+	}{
+		{Options{Params: []string{"version"}}, `unknown param "version"`},
+		{Options{Params: []string{"my-param=spec.replicas"}}, `invalid param name "my-param"`},
+		{Options{Params: []string{"replicas", "replicas=spec.replicas"}}, "param replicas is given more than once"},
+		{Options{Params: []string{"cpu=spec.containers[first]"}}, "bad index [first]"},
+		{Options{Params: []string{"cpu=spec.containers[0"}}, "missing ]"},
+		{Options{Params: []string{"cpu=spec.."}}, "empty key"},
+		{Options{Params: []string{"cpu="}}, "empty selector"},
+		{Options{Params: []string{"replicas"}, Language: Python}, "params are only supported in typescript"},
+		{Options{Params: []string{"replicas"}, Target: TargetPlus}, "params cannot be used with the plus target"},
+	}
+
+	for _, tt := range tests {
+		_, err := Convert(context.Background(), strings.NewReader(paramResources), tt.opts)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%v: got %v, want an error containing %q", tt.opts.Params, err, tt.err)
+		}
+	}
+}
+
+func TestParamsLanguage(t *testing.T) {
+	ctx := context.Background()
+	opts := Options{Language: Python, Params: []string{"replicas"}}
+
+	file := filepath.Join(t.TempDir(), "api.yaml")
+	if err := os.WriteFile(file, []byte(paramResources), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// every path rejects params before generating code reading them
+	convert := map[string]func() error{
+		"Convert": func() error {
+			_, err := Convert(ctx, strings.NewReader(paramResources), opts)
+			return err
+		},
+		"ConvertFiles": func() error {
+			_, err := ConvertFiles(ctx, []string{file}, opts)
+			return err
+		},
+		"ConvertHelmChart": func() error {
+			_, err := ConvertHelmChart(ctx, HelmChart{Path: "./charts/api", Binary: filepath.Join(t.TempDir(), "missing-helm")}, opts)
+			return err
+		},
+		"ConvertKustomization": func() error {
+			_, err := ConvertKustomization(ctx, Kustomization{Path: ".", Binary: filepath.Join(t.TempDir(), "missing-kustomize")}, opts)
+			return err
+		},
+		"Chart": func() error {
+			_, err := Chart("api", nil, opts)
+			return err
+		},
+	}
+
+	for name, fn := range convert {
+		if err := fn(); err == nil || !strings.Contains(err.Error(), "params are only supported in typescript") {
+			t.Errorf("%s: got %v, want an error rejecting params in python", name, err)
+		}
+	}
+}
+
+func TestParamStructTypes(t *testing.T) {
+	manifests := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  template:
+    spec:
+      containers:
+      - name: api
+        image: api:1.4.0
+        ports:
+        - containerPort: 8080
+          name: http
+`
+
+	opts := Options{Params: []string{"ports=$.spec.template.spec.containers[0].ports"}}
+	constructs, err := Convert(context.Background(), strings.NewReader(manifests), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Param{{Name: "ports", Type: "k8s.ContainerPort[]", Default: `[{
+    containerPort: 8080,
+    name: "http",
+}]`}}
+	if got := constructs[0].Params(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got params %v, want %v", got, want)
+	}
+}
+
+func TestParamTypes(t *testing.T) {
+	tests := map[string]string{
+		"3":                  "number",
+		"true":               "boolean",
+		"{name: api}":        "{ [key: string]: string }",
+		"{port: 80, tls: 1}": "{ [key: string]: number }",
+		"{port: 80, a: b}":   "{ port: number; a: string }",
+		"[a, b]":             "string[]",
+		"[a, 1]":             "any[]",
+		"[]":                 "any[]",
+	}
+
+	for input, want := range tests {
+		res, err := parseResource([]byte("kind: Test\nvalue: " + input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := tsType(res.object.get("value")); got != want {
+			t.Errorf("%s: got %q, want %q", input, got, want)
+		}
+	}
+}
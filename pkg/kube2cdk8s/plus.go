@@ -0,0 +1,1019 @@
+package kube2cdk8s
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Target is the kind of constructs objects are converted to.
+type Target string
+
+const (
+	// TargetK8s converts objects to the classes of the k8s bindings, such as
+	// KubeDeployment.
+	TargetK8s Target = "k8s"
+
+	// TargetPlus converts the objects cdk8s-plus has a class for to it,
+	// such as Deployment, and the others to the k8s bindings.
+	TargetPlus Target = "plus"
+)
+
+// plusAlias is the alias cdk8s-plus is imported as.
+const plusAlias = "kplus"
+
+// defaultPlusMinor is the Kubernetes minor version whose cdk8s-plus is
+// imported when Options.KubernetesVersion is not set.
+const defaultPlusMinor = 30
+
+// minPlusMinor is the first Kubernetes minor version cdk8s-plus 2, whose API
+// the constructs are generated with, is published for.
+const minPlusMinor = 22
+
+// plusModule returns the import of the cdk8s-plus of the Kubernetes version
+// set in opts, e.g. cdk8s-plus-28 for 1.28.
+func (opts Options) plusModule() Module {
+	minor, _ := opts.kubernetesMinor()
+	if minor == 0 {
+		minor = defaultPlusMinor
+	}
+
+	return Module{Alias: plusAlias, Path: "cdk8s-plus-" + strconv.Itoa(minor)}
+}
+
+// plusKinds maps the API version and kind of the objects cdk8s-plus has a
+// class for to the function converting them.
+var plusKinds = map[string]func(c *plusConverter){
+	"apps/v1 Deployment":           (*plusConverter).deployment,
+	"apps/v1 StatefulSet":          (*plusConverter).statefulSet,
+	"batch/v1 Job":                 (*plusConverter).job,
+	"batch/v1 CronJob":             (*plusConverter).cronJob,
+	"v1 Service":                   (*plusConverter).service,
+	"v1 ConfigMap":                 (*plusConverter).configMap,
+	"v1 Secret":                    (*plusConverter).secret,
+	"networking.k8s.io/v1 Ingress": (*plusConverter).ingress,
+}
+
+// plusObject is an object converted to a cdk8s-plus construct.
+type plusObject struct {
+	r *resource
+
+	// unsupported are the fields cdk8s-plus cannot express, which keep the
+	// object a construct of the k8s bindings.
+	unsupported []string
+
+	// expr creates the construct.
+	expr *node
+
+	// statements are the calls configuring the construct once created.
+	statements []*node
+
+	// variable is the constant the construct is assigned to, declared when
+	// referenced is set or the resource declares its variable.
+	variable   string
+	referenced bool
+
+	// refs are the objects expr and statements refer to.
+	refs []*plusObject
+
+	// cdk8s records the cdk8s core types the construct refers to.
+	cdk8s map[string]bool
+}
+
+// supported reports whether p is generated with cdk8s-plus.
+func (p *plusObject) supported() bool {
+	return len(p.unsupported) == 0
+}
+
+// planPlus converts resources to cdk8s-plus constructs where possible. The
+// objects are converted in order, so that constructs can refer to the
+// constructs created before them, such as an Ingress to its Services. The
+// variables of resources must be assigned already.
+func planPlus(resources []*resource) {
+	var objects []*plusObject
+
+	for _, r := range resources {
+		if r == nil {
+			continue
+		}
+		convert, ok := plusKinds[r.apiVersion+" "+r.kind]
+		if !ok {
+			continue
+		}
+
+		p := &plusObject{r: r, variable: r.variable, cdk8s: map[string]bool{}}
+
+		c := &plusConverter{p: p, r: r, earlier: objects}
+		convert(c)
+
+		if p.supported() {
+			for _, ref := range p.refs {
+				ref.referenced = true
+			}
+			objects = append(objects, p)
+		}
+		r.plus = p
+	}
+}
+
+// tsPlus renders the cdk8s-plus construct of r in TypeScript.
+func tsPlus(r *resource) string {
+	var b strings.Builder
+	p := r.plus
+
+	b.WriteString(commentBlock(TypeScript, r.comments))
+	if p.referenced || r.declare {
+		fmt.Fprintf(&b, "const %s = ", p.variable)
+	}
+	tsValue(&b, p.expr, 0)
+	b.WriteString(";\n")
+
+	for _, s := range p.statements {
+		tsValue(&b, s, 0)
+		b.WriteString(";\n")
+	}
+
+	return b.String()
+}
+
+// plusConverter converts a single object to cdk8s-plus, recording the
+// fields it cannot express.
+type plusConverter struct {
+	p *plusObject
+	r *resource
+
+	// earlier are the objects converted to cdk8s-plus before this one.
+	earlier []*plusObject
+}
+
+func (c *plusConverter) unsupported(path string) {
+	c.p.unsupported = append(c.p.unsupported, path)
+}
+
+// self returns the variable of the construct, for the statements calling
+// its methods.
+func (c *plusConverter) self() string {
+	c.p.referenced = true
+	return c.p.variable
+}
+
+// find returns the construct created before this one for the object of
+// kind called name in the same namespace, nil if there is none.
+func (c *plusConverter) find(kind, name string) *plusObject {
+	namespace := c.r.object.get("metadata").str("namespace")
+
+	for _, p := range c.earlier {
+		if p.r.kind == kind && p.r.name == name && p.r.object.get("metadata").str("namespace") == namespace {
+			c.p.refs = append(c.p.refs, p)
+			return p
+		}
+	}
+
+	return nil
+}
+
+// construct sets the expression creating the construct of class with props.
+func (c *plusConverter) construct(class string, props *node) {
+	c.p.expr = call("new "+plusAlias+"."+class, code("this"), &node{kind: stringNode, value: c.r.id}, props)
+}
+
+// props returns the props of the construct with the metadata of the object,
+// and reports the top-level fields other than metadata and handled.
+func (c *plusConverter) props(handled ...string) *node {
+	props := &node{kind: mapNode}
+
+	for _, f := range c.r.object.fields {
+		switch {
+		case f.key == "apiVersion" || f.key == "kind" || contains(handled, f.key):
+		case f.key == "metadata":
+			props.set("metadata", c.metadata(f.value, "metadata"))
+		default:
+			c.unsupported(f.key)
+		}
+	}
+
+	return props
+}
+
+// metadata returns the ApiObjectMetadata of the metadata n.
+func (c *plusConverter) metadata(n *node, path string) *node {
+	return c.keep(n, path, "name", "namespace", "labels", "annotations", "finalizers", "ownerReferences")
+}
+
+// keep returns the map n with the keys it can keep as is, reporting the
+// others.
+func (c *plusConverter) keep(n *node, path string, keys ...string) *node {
+	out := &node{kind: mapNode, comments: n.comments}
+	for _, f := range n.fields {
+		if contains(keys, f.key) {
+			out.set(f.key, f.value)
+		} else {
+			c.unsupported(path + "." + f.key)
+		}
+	}
+
+	return out
+}
+
+// fields calls convert with every field of the map n at path, reporting
+// the fields it does not handle.
+func (c *plusConverter) fields(n *node, path string, convert func(f *field, path string) bool) {
+	if n == nil {
+		return
+	}
+
+	for _, f := range n.fields {
+		if !convert(f, path+"."+f.key) {
+			c.unsupported(path + "." + f.key)
+		}
+	}
+}
+
+func (c *plusConverter) deployment() {
+	props := c.props("spec")
+
+	c.fields(c.r.object.get("spec"), "spec", func(f *field, path string) bool {
+		switch f.key {
+		case "replicas":
+			props.set("replicas", f.value)
+		case "minReadySeconds":
+			return c.duration(props, "minReady", f.value)
+		case "progressDeadlineSeconds":
+			return c.duration(props, "progressDeadline", f.value)
+		case "selector":
+			c.selector(props, f.value, path)
+		case "template":
+			c.podTemplate(props, f.value, path)
+		default:
+			return false
+		}
+		return true
+	})
+	c.keepReplicas(props)
+
+	c.construct("Deployment", props)
+}
+
+func (c *plusConverter) statefulSet() {
+	props := c.props("spec")
+
+	c.fields(c.r.object.get("spec"), "spec", func(f *field, path string) bool {
+		switch f.key {
+		case "replicas":
+			props.set("replicas", f.value)
+		case "minReadySeconds":
+			return c.duration(props, "minReady", f.value)
+		case "podManagementPolicy":
+			return c.enum(props, "podManagementPolicy", "PodManagementPolicy", f.value, "OrderedReady", "Parallel")
+		case "serviceName":
+			service := c.find("Service", f.value.value)
+			if service == nil {
+				return false
+			}
+			props.set("service", withComments(code(service.variable), f.value))
+		case "selector":
+			c.selector(props, f.value, path)
+		case "template":
+			c.podTemplate(props, f.value, path)
+		default:
+			return false
+		}
+		return true
+	})
+	c.keepReplicas(props)
+
+	c.construct("StatefulSet", props)
+}
+
+func (c *plusConverter) job() {
+	props := c.props("spec")
+	c.jobSpec(props, c.r.object.get("spec"), "spec")
+	c.construct("Job", props)
+}
+
+// jobSpec adds the JobSpec n to the props of a Job or CronJob.
+func (c *plusConverter) jobSpec(props, n *node, path string) {
+	c.fields(n, path, func(f *field, path string) bool {
+		switch f.key {
+		case "backoffLimit":
+			props.set("backoffLimit", f.value)
+		case "activeDeadlineSeconds":
+			return c.duration(props, "activeDeadline", f.value)
+		case "ttlSecondsAfterFinished":
+			return c.duration(props, "ttlAfterFinished", f.value)
+		case "template":
+			c.podTemplate(props, f.value, path)
+		default:
+			return false
+		}
+		return true
+	})
+}
+
+func (c *plusConverter) cronJob() {
+	props := c.props("spec")
+
+	c.fields(c.r.object.get("spec"), "spec", func(f *field, path string) bool {
+		switch f.key {
+		case "schedule":
+			return c.schedule(props, f.value)
+		case "timeZone", "suspend":
+			props.set(f.key, f.value)
+		case "concurrencyPolicy":
+			return c.enum(props, "concurrencyPolicy", "ConcurrencyPolicy", f.value, "Allow", "Forbid", "Replace")
+		case "startingDeadlineSeconds":
+			return c.duration(props, "startingDeadline", f.value)
+		case "successfulJobsHistoryLimit":
+			props.set("successfulJobsRetained", f.value)
+		case "failedJobsHistoryLimit":
+			props.set("failedJobsRetained", f.value)
+		case "jobTemplate":
+			c.fields(f.value, path, func(f *field, path string) bool {
+				if f.key != "spec" {
+					return false
+				}
+				c.jobSpec(props, f.value, path)
+				return true
+			})
+		default:
+			return false
+		}
+		return true
+	})
+	if props.get("concurrencyPolicy") == nil {
+		// cdk8s-plus forbids concurrent jobs unless told otherwise
+		props.set("concurrencyPolicy", code(plusAlias+".ConcurrencyPolicy.ALLOW"))
+	}
+
+	c.construct("CronJob", props)
+}
+
+// keepReplicas sets the replicas of a workload that has none to the one
+// kubernetes defaults to rather than the two of cdk8s-plus.
+func (c *plusConverter) keepReplicas(props *node) {
+	if props.get("replicas") == nil {
+		props.set("replicas", &node{kind: intNode, value: "1"})
+	}
+}
+
+// cronMacros maps the schedule macros of CronJobs to the cdk8s Cron
+// schedules.
+var cronMacros = map[string]string{
+	"@hourly":   "hourly",
+	"@daily":    "daily",
+	"@midnight": "daily",
+	"@weekly":   "weekly",
+	"@monthly":  "monthly",
+	"@yearly":   "annually",
+	"@annually": "annually",
+}
+
+// schedule sets the schedule of a CronJob to the cdk8s Cron of the cron
+// expression n.
+func (c *plusConverter) schedule(props, n *node) bool {
+	if n.kind != stringNode {
+		return false
+	}
+
+	c.p.cdk8s["Cron"] = true
+
+	if macro, ok := cronMacros[n.value]; ok {
+		props.set("schedule", withComments(call("Cron."+macro), n))
+		return true
+	}
+
+	parts := strings.Fields(n.value)
+	if len(parts) != 5 {
+		return false
+	}
+
+	options := &node{kind: mapNode}
+	for i, key := range []string{"minute", "hour", "day", "month", "weekDay"} {
+		options.set(key, &node{kind: stringNode, value: parts[i]})
+	}
+	props.set("schedule", withComments(call("Cron.schedule", options), n))
+
+	return true
+}
+
+// selector selects the pods of a workload with the LabelSelector n instead
+// of the label cdk8s-plus adds to them.
+func (c *plusConverter) selector(props, n *node, path string) {
+	labels := &node{kind: mapNode}
+	c.fields(n, path, func(f *field, path string) bool {
+		if f.key != "matchLabels" {
+			return false
+		}
+		labels = f.value
+		return true
+	})
+
+	props.set("select", withComments(&node{kind: boolNode, value: "false"}, n))
+	options := &node{kind: mapNode}
+	options.set("labels", labels)
+	c.p.statements = append(c.p.statements, call(c.self()+".select", call(plusAlias+".LabelSelector.of", options)))
+}
+
+// podTemplate adds the PodTemplateSpec n to the props of a workload.
+func (c *plusConverter) podTemplate(props, n *node, path string) {
+	c.fields(n, path, func(f *field, path string) bool {
+		switch f.key {
+		case "metadata":
+			props.set("podMetadata", c.keep(f.value, path, "labels", "annotations"))
+		case "spec":
+			c.podSpec(props, f.value, path)
+		default:
+			return false
+		}
+		return true
+	})
+}
+
+// podSpec adds the PodSpec n to the props of a workload.
+func (c *plusConverter) podSpec(props, n *node, path string) {
+	c.fields(n, path, func(f *field, path string) bool {
+		switch f.key {
+		case "containers", "initContainers":
+			containers := &node{kind: listNode, comments: f.value.comments}
+			for i, item := range f.value.items {
+				containers.items = append(containers.items, c.container(item, fmt.Sprintf("%s[%d]", path, i)))
+			}
+			props.set(f.key, containers)
+		case "restartPolicy":
+			return c.enum(props, "restartPolicy", "RestartPolicy", f.value, "Always", "OnFailure", "Never")
+		case "terminationGracePeriodSeconds":
+			return c.duration(props, "terminationGracePeriod", f.value)
+		case "automountServiceAccountToken", "hostname":
+			props.set(f.key, f.value)
+		default:
+			return false
+		}
+		return true
+	})
+
+	// cdk8s-plus requires the token to be set, while kubernetes leaves it to
+	// the service account, which cannot be told from the pod
+	if n.get("automountServiceAccountToken") == nil {
+		props.set("automountServiceAccountToken", &node{
+			kind:     boolNode,
+			value:    "false",
+			comments: []string{"unset in the manifest, which leaves it to the service account"},
+		})
+	}
+
+	securityContext := &node{kind: mapNode}
+	securityContext.set("ensureNonRoot", &node{kind: boolNode, value: "false"})
+	props.set("securityContext", securityContext)
+}
+
+// container returns the ContainerProps of the Container n.
+func (c *plusConverter) container(n *node, path string) *node {
+	container := &node{kind: mapNode, comments: n.comments}
+
+	c.fields(n, path, func(f *field, path string) bool {
+		switch f.key {
+		case "name", "image", "command", "args", "workingDir":
+			container.set(f.key, f.value)
+		case "imagePullPolicy":
+			return c.enum(container, "imagePullPolicy", "ImagePullPolicy", f.value, "Always", "IfNotPresent", "Never")
+		case "ports":
+			c.containerPorts(container, f.value, path)
+		case "env":
+			c.env(container, f.value, path)
+		case "resources":
+			c.resources(container, f.value, path)
+		default:
+			return false
+		}
+		return true
+	})
+
+	// counter the defaults of cdk8s-plus, which differ from the ones of
+	// kubernetes
+	if container.get("imagePullPolicy") == nil {
+		c.enum(container, "imagePullPolicy", "ImagePullPolicy", &node{kind: stringNode, value: imagePullPolicy(n.str("image"))}, "Always", "IfNotPresent")
+	}
+	if container.get("resources") == nil {
+		container.set("resources", &node{kind: mapNode})
+	}
+	securityContext := &node{kind: mapNode}
+	securityContext.set("ensureNonRoot", &node{kind: boolNode, value: "false"})
+	securityContext.set("readOnlyRootFilesystem", &node{kind: boolNode, value: "false"})
+	securityContext.set("allowPrivilegeEscalation", &node{kind: boolNode, value: "true"})
+	container.set("securityContext", securityContext)
+
+	return container
+}
+
+// imagePullPolicy returns the pull policy kubernetes defaults to for image:
+// Always for the latest tag or no tag, IfNotPresent otherwise.
+func imagePullPolicy(image string) string {
+	if strings.Contains(image, "@") {
+		return "IfNotPresent"
+	}
+
+	i := strings.LastIndex(image, ":")
+	if i < 0 || i < strings.LastIndex(image, "/") || image[i+1:] == "latest" {
+		return "Always"
+	}
+
+	return "IfNotPresent"
+}
+
+// containerPorts sets the ports of a container, as portNumber when it only
+// has one.
+func (c *plusConverter) containerPorts(container, n *node, path string) {
+	if len(n.items) == 1 && len(n.items[0].fields) == 1 && n.items[0].get("containerPort") != nil {
+		container.set("portNumber", withComments(n.items[0].get("containerPort"), n))
+		return
+	}
+
+	ports := &node{kind: listNode, comments: n.comments}
+	for i, item := range n.items {
+		port := &node{kind: mapNode, comments: item.comments}
+		c.fields(item, fmt.Sprintf("%s[%d]", path, i), func(f *field, path string) bool {
+			switch f.key {
+			case "containerPort":
+				port.set("number", f.value)
+			case "name", "hostPort":
+				port.set(f.key, f.value)
+			case "hostIP":
+				port.set("hostIp", f.value)
+			case "protocol":
+				return c.enum(port, "protocol", "Protocol", f.value, "TCP", "UDP", "SCTP")
+			default:
+				return false
+			}
+			return true
+		})
+		ports.items = append(ports.items, port)
+	}
+	container.set("ports", ports)
+}
+
+// env sets the environment variables of a container that have a value.
+func (c *plusConverter) env(container, n *node, path string) {
+	variables := &node{kind: mapNode, comments: n.comments}
+
+	for i, item := range n.items {
+		value := &node{kind: stringNode}
+		comments := append([]string(nil), item.comments...)
+		c.fields(item, fmt.Sprintf("%s[%d]", path, i), func(f *field, path string) bool {
+			switch f.key {
+			case "name":
+			case "value":
+				value.value = f.value.value
+			default:
+				return false
+			}
+			comments = append(comments, f.value.comments...)
+			return true
+		})
+		variable := call(plusAlias+".EnvValue.fromValue", value)
+		variable.comments = comments
+		variables.set(item.str("name"), variable)
+	}
+
+	container.set("envVariables", variables)
+}
+
+// resources sets the resources of a container from its requests and limits.
+func (c *plusConverter) resources(container, n *node, path string) {
+	resources := &node{kind: mapNode, comments: n.comments}
+
+	c.fields(n, path, func(f *field, path string) bool {
+		bound := map[string]string{"requests": "request", "limits": "limit"}[f.key]
+		if bound == "" {
+			return false
+		}
+
+		c.fields(f.value, path, func(f *field, path string) bool {
+			var key string
+			var amount *node
+			switch f.key {
+			case "cpu":
+				key, amount = "cpu", c.cpu(f.value)
+			case "memory":
+				key, amount = "memory", c.size(f.value)
+			case "ephemeral-storage":
+				key, amount = "ephemeralStorage", c.size(f.value)
+			}
+			if amount == nil {
+				return false
+			}
+
+			limits := resources.get(key)
+			if limits == nil {
+				limits = &node{kind: mapNode}
+				resources.set(key, limits)
+			}
+			limits.set(bound, withComments(amount, f.value))
+			return true
+		})
+		return true
+	})
+
+	container.set("resources", resources)
+}
+
+// cpu returns the kplus.Cpu of the quantity n, nil if it has none.
+func (c *plusConverter) cpu(n *node) *node {
+	if millis := strings.TrimSuffix(n.value, "m"); n.kind == stringNode && millis != n.value {
+		if _, err := strconv.Atoi(millis); err == nil {
+			return call(plusAlias+".Cpu.millis", &node{kind: intNode, value: millis})
+		}
+		return nil
+	}
+
+	if _, err := strconv.ParseFloat(n.value, 64); err != nil || n.kind == mapNode || n.kind == listNode {
+		return nil
+	}
+
+	return call(plusAlias+".Cpu.units", &node{kind: floatNode, value: n.value})
+}
+
+// sizeUnits maps the binary suffixes of quantities to the cdk8s Size
+// methods.
+var sizeUnits = map[string]string{
+	"Ki": "kibibytes",
+	"Mi": "mebibytes",
+	"Gi": "gibibytes",
+	"Ti": "tebibytes",
+}
+
+// size returns the cdk8s Size of the quantity n, nil if it has none.
+func (c *plusConverter) size(n *node) *node {
+	if n.kind != stringNode || len(n.value) < 3 {
+		return nil
+	}
+
+	number, suffix := n.value[:len(n.value)-2], n.value[len(n.value)-2:]
+	unit, ok := sizeUnits[suffix]
+	if _, err := strconv.Atoi(number); err != nil || !ok {
+		return nil
+	}
+
+	c.p.cdk8s["Size"] = true
+
+	return call("Size."+unit, &node{kind: intNode, value: number})
+}
+
+// duration sets key to the cdk8s Duration of the number of seconds n.
+func (c *plusConverter) duration(props *node, key string, n *node) bool {
+	if n.kind != intNode {
+		return false
+	}
+
+	c.p.cdk8s["Duration"] = true
+	props.set(key, withComments(call("Duration.seconds", &node{kind: intNode, value: n.value}), n))
+
+	return true
+}
+
+// enum sets key to the member of the cdk8s-plus enum class matching n, one
+// of values.
+func (c *plusConverter) enum(props *node, key, class string, n *node, values ...string) bool {
+	if n.kind != stringNode || !contains(values, n.value) {
+		return false
+	}
+
+	props.set(key, withComments(code(plusAlias+"."+class+"."+screamingSnake(n.value)), n))
+
+	return true
+}
+
+func (c *plusConverter) service() {
+	props := c.props("spec")
+	spec := c.r.object.get("spec")
+
+	var selector []*node
+	c.fields(spec, "spec", func(f *field, path string) bool {
+		switch f.key {
+		case "type":
+			return c.enum(props, "type", "ServiceType", f.value, "ClusterIP", "NodePort", "LoadBalancer", "ExternalName")
+		case "ports":
+			c.servicePorts(props, f.value, path)
+		case "clusterIP", "externalIPs", "externalName", "loadBalancerSourceRanges":
+			props.set(f.key, f.value)
+		case "selector":
+			for _, label := range f.value.fields {
+				selector = append(selector, call(c.p.variable+".selectLabel", &node{kind: stringNode, value: label.key}, label.value))
+			}
+		default:
+			return false
+		}
+		return true
+	})
+
+	if deployment := c.exposing(); deployment != nil {
+		options := &node{kind: mapNode}
+		options.set("name", &node{kind: stringNode, value: c.r.name})
+		if t := props.get("type"); t != nil {
+			options.set("serviceType", t)
+		}
+		if ports := props.get("ports"); ports != nil {
+			options.set("ports", ports)
+		}
+		c.p.expr = call(deployment.variable+".exposeViaService", options)
+		return
+	}
+
+	if len(selector) > 0 {
+		c.self()
+		c.p.statements = append(c.p.statements, selector...)
+	}
+	c.construct("Service", props)
+}
+
+// exposing returns the Deployment created before the Service that selects
+// exactly its pods, when the Service can be created with its
+// exposeViaService method.
+func (c *plusConverter) exposing() *plusObject {
+	metadata := c.r.object.get("metadata")
+	if len(metadata.fields) != 1 || metadata.get("name") == nil {
+		return nil
+	}
+
+	spec := c.r.object.get("spec")
+	for _, f := range spec.fields {
+		if !contains([]string{"type", "selector", "ports"}, f.key) {
+			return nil
+		}
+	}
+
+	selector := spec.get("selector")
+	if selector == nil {
+		return nil
+	}
+
+	for _, p := range c.earlier {
+		if p.r.kind != "Deployment" || p.r.object.get("metadata").str("namespace") != "" {
+			continue
+		}
+		if sameLabels(p.r.object.get("spec").get("selector").get("matchLabels"), selector) {
+			c.p.refs = append(c.p.refs, p)
+			return p
+		}
+	}
+
+	return nil
+}
+
+// sameLabels reports whether the label maps a and b are equal.
+func sameLabels(a, b *node) bool {
+	if a == nil || b == nil || len(a.fields) != len(b.fields) {
+		return false
+	}
+
+	for _, f := range a.fields {
+		if b.str(f.key) != f.value.value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// servicePorts sets the ports of a Service.
+func (c *plusConverter) servicePorts(props, n *node, path string) {
+	ports := &node{kind: listNode, comments: n.comments}
+
+	for i, item := range n.items {
+		port := &node{kind: mapNode, comments: item.comments}
+		c.fields(item, fmt.Sprintf("%s[%d]", path, i), func(f *field, path string) bool {
+			switch f.key {
+			case "port", "name", "nodePort":
+				port.set(f.key, f.value)
+			case "targetPort":
+				if f.value.kind != intNode {
+					return false
+				}
+				port.set(f.key, f.value)
+			case "protocol":
+				return c.enum(port, "protocol", "Protocol", f.value, "TCP", "UDP", "SCTP")
+			default:
+				return false
+			}
+			return true
+		})
+		ports.items = append(ports.items, port)
+	}
+
+	props.set("ports", ports)
+}
+
+func (c *plusConverter) configMap() {
+	props := c.props("data", "binaryData", "immutable")
+
+	for _, f := range c.r.object.fields {
+		if contains([]string{"data", "binaryData", "immutable"}, f.key) {
+			props.set(f.key, f.value)
+		}
+	}
+
+	c.construct("ConfigMap", props)
+}
+
+func (c *plusConverter) secret() {
+	props := c.props("data", "stringData", "immutable", "type")
+	stringData := &node{kind: mapNode}
+
+	for _, f := range c.r.object.fields {
+		switch f.key {
+		case "type":
+			if f.value.value != "Opaque" {
+				c.unsupported("type")
+			}
+		case "immutable":
+			props.set(f.key, f.value)
+		case "stringData":
+			for _, d := range f.value.fields {
+				stringData.set(d.key, d.value)
+			}
+		case "data":
+			// cdk8s-plus only takes string data, which is encoded for us
+			for _, d := range f.value.fields {
+				decoded, err := base64.StdEncoding.DecodeString(d.value.value)
+				if err != nil || !utf8.Valid(decoded) {
+					c.unsupported("data." + d.key)
+					continue
+				}
+				stringData.set(d.key, withComments(&node{kind: stringNode, value: string(decoded)}, d.value))
+			}
+		}
+	}
+
+	if len(stringData.fields) > 0 {
+		props.set("stringData", stringData)
+	}
+
+	c.construct("Secret", props)
+}
+
+func (c *plusConverter) ingress() {
+	props := c.props("spec")
+
+	c.fields(c.r.object.get("spec"), "spec", func(f *field, path string) bool {
+		switch f.key {
+		case "ingressClassName":
+			props.set("className", f.value)
+		case "defaultBackend":
+			if backend := c.backend(f.value, path); backend != nil {
+				props.set("defaultBackend", backend)
+			}
+		case "rules":
+			c.ingressRules(props, f.value, path)
+		case "tls":
+			c.ingressTLS(props, f.value, path)
+		default:
+			return false
+		}
+		return true
+	})
+
+	c.construct("Ingress", props)
+}
+
+// ingressRules sets the rules of an Ingress, one for each path of a host.
+func (c *plusConverter) ingressRules(props, n *node, path string) {
+	rules := &node{kind: listNode, comments: n.comments}
+
+	for i, rule := range n.items {
+		rulePath := fmt.Sprintf("%s[%d]", path, i)
+		host := rule.get("host")
+
+		c.fields(rule, rulePath, func(f *field, path string) bool {
+			switch f.key {
+			case "host":
+			case "http":
+				c.fields(f.value, path, func(f *field, path string) bool {
+					if f.key != "paths" {
+						return false
+					}
+					for j, p := range f.value.items {
+						out := &node{kind: mapNode, comments: p.comments}
+						if host != nil {
+							out.set("host", host)
+						}
+						c.fields(p, fmt.Sprintf("%s[%d]", path, j), func(f *field, path string) bool {
+							switch f.key {
+							case "path":
+								out.set("path", f.value)
+							case "pathType":
+								return c.enum(out, "pathType", "HttpIngressPathType", f.value, "Prefix", "Exact", "ImplementationSpecific")
+							case "backend":
+								backend := c.backend(f.value, path)
+								if backend == nil {
+									return true
+								}
+								out.set("backend", backend)
+							default:
+								return false
+							}
+							return true
+						})
+						rules.items = append(rules.items, out)
+					}
+					return true
+				})
+			default:
+				return false
+			}
+			return true
+		})
+
+		if rule.get("http") == nil {
+			c.unsupported(rulePath)
+		}
+	}
+
+	props.set("rules", rules)
+}
+
+// backend returns the IngressBackend of the IngressBackend n, which must
+// refer to a Service created before by the number of its port.
+func (c *plusConverter) backend(n *node, path string) *node {
+	service := n.get("service")
+	port := service.get("port").get("number")
+	if len(n.fields) != 1 || service == nil || port == nil {
+		c.unsupported(path)
+		return nil
+	}
+
+	p := c.find("Service", service.str("name"))
+	if p == nil {
+		c.unsupported(path + ".service.name")
+		return nil
+	}
+
+	options := &node{kind: mapNode}
+	options.set("port", port)
+
+	return withComments(call(plusAlias+".IngressBackend.fromService", code(p.variable), options), n)
+}
+
+// ingressTLS sets the TLS configuration of an Ingress, whose secrets must
+// have been created before.
+func (c *plusConverter) ingressTLS(props, n *node, path string) {
+	tls := &node{kind: listNode, comments: n.comments}
+
+	for i, item := range n.items {
+		out := &node{kind: mapNode, comments: item.comments}
+		c.fields(item, fmt.Sprintf("%s[%d]", path, i), func(f *field, path string) bool {
+			switch f.key {
+			case "hosts":
+				out.set("hosts", f.value)
+			case "secretName":
+				secret := c.find("Secret", f.value.value)
+				if secret == nil {
+					return false
+				}
+				out.set("secret", withComments(code(secret.variable), f.value))
+			default:
+				return false
+			}
+			return true
+		})
+		tls.items = append(tls.items, out)
+	}
+
+	props.set("tls", tls)
+}
+
+// code returns a node written as the code s.
+func code(s string) *node {
+	return &node{kind: codeNode, value: s}
+}
+
+// call returns a node calling function with args.
+func call(function string, args ...*node) *node {
+	return &node{kind: callNode, value: function, items: args}
+}
+
+// withComments returns n with the comments of the value it replaces.
+func withComments(n, replaced *node) *node {
+	out := *n
+	out.comments = replaced.comments
+
+	return &out
+}
+
+// screamingSnake returns the enum member of a Kubernetes enum value, e.g.
+// IF_NOT_PRESENT for IfNotPresent and CLUSTER_IP for ClusterIP.
+func screamingSnake(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) &&
+			(unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,364 @@
+package kube2cdk8s
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bradleyjkemp/cupaloy"
+)
+
+const plusManifests = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-config
+data:
+  LOG_LEVEL: info
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: web-tls
+data:
+  password: aHVudGVyMg==
+---
+# the web frontend
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+spec:
+  replicas: 3
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      automountServiceAccountToken: true
+      containers:
+      - name: web
+        image: nginx:1.25
+        imagePullPolicy: IfNotPresent
+        ports:
+        - containerPort: 80
+        env:
+        - name: LOG_LEVEL
+          value: debug # noisy
+        resources:
+          requests:
+            cpu: 100m
+            memory: 128Mi
+          limits:
+            cpu: "1"
+            memory: 1Gi
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  type: NodePort
+  selector:
+    app: web
+  ports:
+  - port: 80
+    targetPort: 80
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  ingressClassName: nginx
+  rules:
+  - host: example.com
+    http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: web
+            port:
+              number: 80
+  tls:
+  - hosts:
+    - example.com
+    secretName: web-tls
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: backup
+spec:
+  schedule: "*/5 * * * *"
+  concurrencyPolicy: Forbid
+  jobTemplate:
+    spec:
+      backoffLimit: 2
+      template:
+        spec:
+          restartPolicy: OnFailure
+          automountServiceAccountToken: false
+          containers:
+          - name: backup
+            image: backup:1.0
+            args: ["--all"]
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  serviceName: db
+  selector:
+    matchLabels:
+      app: db
+  template:
+    metadata:
+      labels:
+        app: db
+    spec:
+      containers:
+      - name: db
+        image: postgres:16
+        volumeMounts:
+        - name: data
+          mountPath: /var/lib/postgresql
+  volumeClaimTemplates:
+  - metadata:
+      name: data
+`
+
+func TestPlusChart(t *testing.T) {
+	opts := Options{Target: TargetPlus}
+
+	constructs, err := Convert(context.Background(), strings.NewReader(plusManifests), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chart, err := Chart("", constructs, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cupaloy.Snapshot(chart)
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestPlusUnsupported(t *testing.T) {
+	constructs, err := Convert(context.Background(), strings.NewReader(plusManifests), Options{Target: TargetPlus})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range constructs {
+		var want []string
+		if c.Kind == "StatefulSet" {
+			want = []string{"spec.serviceName", "spec.template.spec.containers[0].volumeMounts", "spec.volumeClaimTemplates"}
+		}
+		if got := c.Unsupported(); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s %s: got unsupported %v, want %v", c.Kind, c.Name, got, want)
+		}
+	}
+}
+
+func TestPlusDefaults(t *testing.T) {
+
+	// nothing is left to the defaults of cdk8s-plus, which differ from the
+	// ones of kubernetes
+	manifests := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      automountServiceAccountToken: false
+      containers:
+      - name: web
+        image: nginx
+      - name: sidecar
+        image: envoyproxy/envoy:v1.29.0
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: backup
+spec:
+  schedule: "@daily"
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          restartPolicy: Never
+          automountServiceAccountToken: true
+          containers:
+          - name: backup
+            image: registry.example.com:5000/backup@sha256:0123456789abcdef
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+spec:
+  template:
+    spec:
+      restartPolicy: Never
+      containers:
+      - name: migrate
+        image: migrate:latest
+`
+
+	constructs, err := Convert(context.Background(), strings.NewReader(manifests), Options{Target: TargetPlus})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the Job leaves the token to its service account, which cdk8s-plus
+	// cannot, so it is turned off
+	if got := constructs[2].Unsupported(); len(got) != 0 {
+		t.Errorf("got unsupported %v", got)
+	}
+	if !strings.Contains(constructs[2].Code, "automountServiceAccountToken: false,") {
+		t.Errorf("the token is not turned off:\n%s", constructs[2].Code)
+	}
+
+	err = cupaloy.Snapshot(Join(constructs, TypeScript))
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestImagePullPolicy(t *testing.T) {
+	tests := map[string]string{
+		"nginx":                               "Always",
+		"nginx:latest":                        "Always",
+		"nginx:1.25":                          "IfNotPresent",
+		"registry.example.com:5000/api":       "Always",
+		"registry.example.com:5000/api:1.4.0": "IfNotPresent",
+		"nginx@sha256:0123456789abcdef":       "IfNotPresent",
+	}
+
+	for image, want := range tests {
+		if got := imagePullPolicy(image); got != want {
+			t.Errorf("%s: got %s, want %s", image, got, want)
+		}
+	}
+}
+
+func TestPlusReferences(t *testing.T) {
+	manifests := `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  rules:
+  - http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: web
+            port:
+              number: 80
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: prod
+spec:
+  selector:
+    app: web
+  ports:
+  - port: 80
+`
+
+	constructs, err := Convert(context.Background(), strings.NewReader(manifests), Options{Target: TargetPlus})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the Service is created after the Ingress, which cannot refer to it
+	want := []string{"spec.rules[0].http.paths[0].backend.service.name"}
+	if got := constructs[0].Unsupported(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got unsupported %v, want %v", got, want)
+	}
+
+	err = cupaloy.Snapshot(Join(constructs, TypeScript))
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestPlusLanguage(t *testing.T) {
+	opts := Options{Language: Python, Target: TargetPlus}
+
+	if _, err := Convert(context.Background(), strings.NewReader(plusManifests), opts); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestPlusModule(t *testing.T) {
+	tests := map[string]string{
+		"":     "cdk8s-plus-30",
+		"1.28": "cdk8s-plus-28",
+		"1.31": "cdk8s-plus-31",
+	}
+
+	for version, want := range tests {
+		opts := Options{Target: TargetPlus, KubernetesVersion: version}
+		constructs, err := Convert(context.Background(), strings.NewReader(plusManifests), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		chart, err := Chart("", constructs, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(chart, "import * as kplus from '"+want+"';") {
+			t.Errorf("%q: %s is not imported:\n%s", version, want, chart)
+		}
+	}
+
+	opts := Options{Target: TargetPlus, KubernetesVersion: "1.21"}
+	if _, err := Convert(context.Background(), strings.NewReader(plusManifests), opts); err == nil || !strings.Contains(err.Error(), "cdk8s-plus requires kubernetes 1.22") {
+		t.Errorf("got %v, want an error for kubernetes 1.21", err)
+	}
+}
+
+func TestScreamingSnake(t *testing.T) {
+	tests := map[string]string{
+		"IfNotPresent": "IF_NOT_PRESENT",
+		"ClusterIP":    "CLUSTER_IP",
+		"TCP":          "TCP",
+		"OnFailure":    "ON_FAILURE",
+	}
+
+	for value, want := range tests {
+		if got := screamingSnake(value); got != want {
+			t.Errorf("%s: got %s, want %s", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package kube2cdk8s
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	pyLowerUpper = regexp.MustCompile(`([\p{Ll}\d])(\p{Lu})`)
+	pyUpperWord  = regexp.MustCompile(`(\p{Lu}+)(\p{Lu}\p{Ll}+)`)
+)
+
+var pyKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true,
+	"assert": true, "async": true, "await": true, "break": true, "class": true,
+	"continue": true, "def": true, "del": true, "elif": true, "else": true,
+	"except": true, "finally": true, "for": true, "from": true, "global": true,
+	"if": true, "import": true, "in": true, "is": true, "lambda": true,
+	"nonlocal": true, "not": true, "or": true, "pass": true, "raise": true,
+	"return": true, "try": true, "while": true, "with": true, "yield": true,
+}
+
+// python renders r as a cdk8s Python construct.
+func python(r *resource) string {
+	var b strings.Builder
+	s := r.scope
+
+	b.WriteString(commentBlock(Python, r.comments))
+
+	if r.declare {
+		b.WriteString(r.variable + " = ")
+	}
+
+	props := r.props()
+	var patches []*field
+	if s.apiObject {
+		s.cdk8s["ApiObject"] = true
+		props, patches = apiObjectProps(r)
+		fmt.Fprintf(&b, "ApiObject(self, %s", quote(r.id))
+	} else {
+		fmt.Fprintf(&b, "%s.%s(self, %s", s.module, s.className(), quote(r.id))
+	}
+
+	if len(props.fields) > 0 {
+		b.WriteString(",")
+		pyFields(&b, s, s.propsType(), props, 0)
+		b.WriteString("\n")
+	}
+	b.WriteString(")")
+
+	if len(patches) > 0 {
+		s.cdk8s["JsonPatch"] = true
+		if r.declare {
+			// add_json_patch returns nothing to assign
+			b.WriteString("\n" + r.variable)
+		}
+		b.WriteString(".add_json_patch(")
+		for _, f := range patches {
+			writeComments(&b, Python, f.value.comments, 1)
+			b.WriteString("\n" + indent(1) + "JsonPatch.add(" + quote(jsonPointer(f.key)) + ", ")
+			pyLiteral(&b, f.value, 1)
+			b.WriteString("),")
+		}
+		b.WriteString("\n)")
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// pyFields writes the fields of n as keyword arguments of struct parent
+// declared in scope s.
+func pyFields(b *strings.Builder, s *scope, parent string, n *node, level int) {
+	for _, f := range n.fields {
+		writeComments(b, Python, f.value.comments, level+1)
+		b.WriteString("\n" + indent(level+1) + pyName(f.key) + "=")
+		pyValue(b, s, s.fieldType(parent, f.key, f.value), f.value, level+1)
+		b.WriteString(",")
+	}
+}
+
+// pyValue writes n as a Python expression of type t indented at level.
+func pyValue(b *strings.Builder, s *scope, t typeRef, n *node, level int) {
+	switch {
+	case t.kind == structType && n.kind == mapNode:
+		// cdk8s core types are imported by name
+		if module := s.moduleOf(t); module != cdk8sModule {
+			b.WriteString(module + ".")
+		}
+		b.WriteString(t.name + "(")
+		if len(n.fields) > 0 {
+			pyFields(b, s, t.name, n, level)
+			b.WriteString("\n" + indent(level))
+		}
+		b.WriteString(")")
+
+	case t.kind == listType && n.kind == listNode:
+		writeList(b, Python, n.items, level, func(item *node, level int) {
+			pyValue(b, s, *t.elem, item, level)
+		})
+
+	case t.kind == mapType && n.kind == mapNode:
+		pyDict(b, n, level, func(item *node, level int) {
+			pyValue(b, s, *t.elem, item, level)
+		})
+
+	case t.kind == quantityType:
+		pyUnion(b, s.module, "Quantity", n)
+
+	case t.kind == intOrStringType:
+		pyUnion(b, s.module, "IntOrString", n)
+
+	default:
+		pyLiteral(b, n, level)
+	}
+}
+
+// pyUnion writes n through the from_number or from_string factory of a
+// cdk8s union class such as Quantity.
+func pyUnion(b *strings.Builder, module, class string, n *node) {
+	switch n.kind {
+	case intNode, floatNode:
+		fmt.Fprintf(b, "%s.%s.from_number(%s)", module, class, n.value)
+	default:
+		fmt.Fprintf(b, "%s.%s.from_string(%s)", module, class, quote(n.value))
+	}
+}
+
+// pyLiteral writes n as a plain Python literal.
+func pyLiteral(b *strings.Builder, n *node, level int) {
+	switch n.kind {
+	case nullNode:
+		b.WriteString("None")
+	case boolNode:
+		if n.value == "true" {
+			b.WriteString("True")
+		} else {
+			b.WriteString("False")
+		}
+	case stringNode:
+		b.WriteString(quote(n.value))
+	case mapNode:
+		pyDict(b, n, level, func(item *node, level int) {
+			pyLiteral(b, item, level)
+		})
+	case listNode:
+		writeList(b, Python, n.items, level, func(item *node, level int) {
+			pyLiteral(b, item, level)
+		})
+	default:
+		b.WriteString(n.value)
+	}
+}
+
+// pyDict writes n as a dict literal keeping its keys as they are.
+func pyDict(b *strings.Builder, n *node, level int, value func(n *node, level int)) {
+	if len(n.fields) == 0 {
+		b.WriteString("{}")
+		return
+	}
+
+	b.WriteString("{")
+	for _, f := range n.fields {
+		writeComments(b, Python, f.value.comments, level+1)
+		b.WriteString("\n" + indent(level+1) + quote(f.key) + ": ")
+		value(f.value, level+1)
+		b.WriteString(",")
+	}
+	b.WriteString("\n" + indent(level) + "}")
+}
+
+// pyName returns the snake_case keyword argument jsii generates for the
+// camelCase property key, e.g. matchLabels becomes match_labels.
+func pyName(key string) string {
+	name := key
+	if strings.ToLower(key) != key {
+		name = pyLowerUpper.ReplaceAllString(name, "${1}_${2}")
+		name = pyUpperWord.ReplaceAllString(name, "${1}_${2}")
+		name = strings.ToLower(name)
+	}
+
+	if pyKeywords[name] {
+		name += "_"
+	}
+
+	return name
+}
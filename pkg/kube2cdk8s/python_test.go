@@ -0,0 +1,107 @@
+package kube2cdk8s
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	"github.com/smallcase/kube2cdk8s/util"
+
+	"github.com/bradleyjkemp/cupaloy"
+)
+
+func TestKube2CDK8SPythonDeployment(t *testing.T) {
+
+	deployment := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-deployment
+  namespace: my-namespace
+spec:
+  selector:
+    matchLabels:
+      app: my-deployment
+  replicas: 3
+  template:
+    metadata:
+      labels:
+        app: my-deployment
+    spec:
+      hostIPC: false
+      containers:
+      - name: my-deployment
+        image: my-image
+        imagePullPolicy: Always
+        ports:
+        - containerPort: 8080
+        resources:
+          limits:
+            cpu: 500m
+            memory: 1Gi
+        readinessProbe:
+          httpGet:
+            path: /healthz
+            port: http
+      volumes:
+      - name: tmp
+        emptyDir: {}
+`
+	deploymentFile, err := util.CreateTempFile([]byte(deployment))
+	if err != nil {
+		log.Println(err.Error())
+	}
+
+	d, err := Kube2CDK8SLanguage(deploymentFile.Name(), Python)
+	if err != nil {
+		log.Println(err.Error())
+	}
+
+	err = cupaloy.Snapshot(d)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	defer os.Remove(deploymentFile.Name())
+}
+
+func TestKube2CDK8SPythonMultiple(t *testing.T) {
+
+	manifests := `---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: my-service-account
+  namespace: my-namespace
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: my-network-policy
+spec:
+  podSelector: {}
+  ingress:
+  - from:
+    - podSelector:
+        matchLabels:
+          app: my-app
+    ports:
+    - port: 8080
+`
+	manifestsFile, err := util.CreateTempFile([]byte(manifests))
+	if err != nil {
+		log.Println(err.Error())
+	}
+
+	d, err := Kube2CDK8SMultipleLanguage(manifestsFile.Name(), Python)
+	if err != nil {
+		log.Println(err.Error())
+	}
+
+	err = cupaloy.Snapshot(d)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	defer os.Remove(manifestsFile.Name())
+}
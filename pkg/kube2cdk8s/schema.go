@@ -0,0 +1,925 @@
+package kube2cdk8s
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// typeKind is the kind of cdk8s type a property is generated as.
+type typeKind int
+
+const (
+	// inferredType properties are scalars, lists of scalars or free-form maps
+	// whose literal type is taken from the YAML value.
+	inferredType typeKind = iota
+	structType
+	listType
+	mapType
+	quantityType
+	intOrStringType
+	jsonType
+
+	// unknownType properties are maps or lists of maps that k8sStructs does
+	// not list. Objects holding one are generated as a plain ApiObject, since
+	// the struct the bindings declare for it is not known.
+	unknownType
+)
+
+// typeRef is the cdk8s type of a property.
+type typeRef struct {
+	kind typeKind
+	name string
+	elem *typeRef
+
+	// module is the module a struct is declared in when it is not the one
+	// of the resource, such as cdk8sModule.
+	module string
+}
+
+// cdk8sModule is the module of the cdk8s core classes and structs, such as
+// ApiObject and ApiObjectMetadata.
+const cdk8sModule = "cdk8s"
+
+// scope is where the class and structs of a resource are declared: the
+// generated k8s bindings, the bindings cdk8s import generates for a custom
+// resource, or cdk8s itself for a plain ApiObject.
+type scope struct {
+	// kind is the kind of the resource.
+	kind string
+
+	// group is the API group of the resource.
+	group string
+
+	// suffix is appended by cdk8s import to the class and the structs of
+	// the API version of a builtin resource, see versionSuffix.
+	suffix string
+
+	// module is the alias the class and structs are qualified with.
+	module string
+
+	// custom is set for custom resources, whose structs are named after
+	// their parent struct and property.
+	custom bool
+
+	// apiObject is set for resources generated as a plain ApiObject.
+	apiObject bool
+
+	// cdk8s records the cdk8s core types the generated code refers to.
+	cdk8s map[string]bool
+
+	// unknown lists the fields of unknownType that made a builtin resource a
+	// plain ApiObject.
+	unknown []string
+}
+
+// className returns the class of the construct of the resource.
+func (s *scope) className() string {
+	switch {
+	case s.apiObject:
+		return "ApiObject"
+	case s.custom:
+		return s.kind
+	}
+
+	return "Kube" + s.kind + s.suffix
+}
+
+// propsType returns the props struct of the construct of the resource.
+func (s *scope) propsType() string {
+	return s.className() + "Props"
+}
+
+// fieldType returns the type of property key of struct parent holding value,
+// see fieldType. The structs of custom resources are named like cdk8s import
+// names them, e.g. spec.issuerRef of a Certificate is a
+// CertificateSpecIssuerRef.
+func (s *scope) fieldType(parent, key string, value *node) typeRef {
+	if parent == "ApiObjectMetadata" {
+		switch key {
+		case "labels", "annotations":
+			return parseTypeRef("map")
+		case "ownerReferences":
+			return typeRef{kind: listType, elem: &typeRef{kind: structType, name: "OwnerReference", module: cdk8sModule}}
+		}
+		return typeRef{kind: inferredType}
+	}
+
+	if !s.custom && !s.apiObject {
+		return s.versioned(fieldType(s.unversioned(parent), key, value))
+	}
+
+	if parent == s.propsType() && key == "metadata" {
+		return typeRef{kind: structType, name: "ApiObjectMetadata", module: cdk8sModule}
+	}
+
+	name := strings.TrimSuffix(parent, "Props") + upperFirst(key)
+	switch value.kind {
+	case mapNode:
+		return typeRef{kind: structType, name: name}
+	case listNode:
+		if len(value.items) > 0 && value.items[0].kind == mapNode {
+			return typeRef{kind: listType, elem: &typeRef{kind: structType, name: name}}
+		}
+	}
+
+	return typeRef{kind: inferredType}
+}
+
+// unversioned returns the struct of the k8s bindings named parent without
+// the version suffix of the resource, as it is listed in k8sStructs.
+func (s *scope) unversioned(parent string) string {
+	if parent == s.propsType() {
+		return "Kube" + s.kind + "Props"
+	}
+	if s.isVersioned(strings.TrimSuffix(parent, s.suffix)) {
+		return strings.TrimSuffix(parent, s.suffix)
+	}
+
+	return parent
+}
+
+// versioned returns t with the version suffix of the resource appended to the
+// structs its API version declares.
+func (s *scope) versioned(t typeRef) typeRef {
+	if t.elem != nil {
+		elem := s.versioned(*t.elem)
+		t.elem = &elem
+	}
+	if t.kind == structType && s.isVersioned(t.name) {
+		t.name += s.suffix
+	}
+
+	return t
+}
+
+// unknownFields returns the paths of the fields of unknownType in n, a value
+// of struct parent, prefixing them with path.
+func (s *scope) unknownFields(parent, path string, n *node) []string {
+	var paths []string
+	for _, f := range n.fields {
+		paths = append(paths, s.unknownPaths(s.fieldType(parent, f.key, f.value), path+f.key, f.value)...)
+	}
+
+	return paths
+}
+
+// unknownPaths returns the paths of the fields of unknownType in n, a value
+// of type t at path.
+func (s *scope) unknownPaths(t typeRef, path string, n *node) []string {
+	var paths []string
+	switch {
+	case t.kind == unknownType:
+		paths = append(paths, path)
+
+	case t.kind == structType && n.kind == mapNode:
+		paths = append(paths, s.unknownFields(t.name, path+".", n)...)
+
+	case t.kind == listType && n.kind == listNode:
+		for i, item := range n.items {
+			paths = append(paths, s.unknownPaths(*t.elem, fmt.Sprintf("%s[%d]", path, i), item)...)
+		}
+
+	case t.kind == mapType && n.kind == mapNode:
+		for _, f := range n.fields {
+			paths = append(paths, s.unknownPaths(*t.elem, path+"."+f.key, f.value)...)
+		}
+	}
+
+	return paths
+}
+
+// moduleOf returns the module a struct of type t is qualified with, recording
+// the cdk8s core types in use.
+func (s *scope) moduleOf(t typeRef) string {
+	if t.module == cdk8sModule {
+		s.cdk8s[t.name] = true
+		return cdk8sModule
+	}
+
+	return s.module
+}
+
+// k8sStructs lists, for each struct in cdk8s's generated k8s bindings, the
+// properties that are not plain scalars. Properties missing from a struct are
+// inferred from their value, see fieldType.
+//
+// Types are written as "Name" for a struct, "[]Name" for a list of structs,
+// "map" for a free-form map of strings, "map[Quantity]" for a map of
+// quantities, "Quantity", "IntOrString" and "json" for arbitrary data.
+var k8sStructs = map[string]map[string]string{
+	"KubeConfigMapProps": {
+		"data":       "map",
+		"binaryData": "map",
+	},
+	"KubeSecretProps": {
+		"data":       "map",
+		"stringData": "map",
+	},
+	"KubeServiceAccountProps": {
+		"secrets":          "[]ObjectReference",
+		"imagePullSecrets": "[]LocalObjectReference",
+	},
+	"KubeRoleProps": {
+		"rules": "[]PolicyRule",
+	},
+	"KubeClusterRoleProps": {
+		"rules":           "[]PolicyRule",
+		"aggregationRule": "AggregationRule",
+	},
+	"KubeRoleBindingProps": {
+		"roleRef":  "RoleRef",
+		"subjects": "[]Subject",
+	},
+	"KubeClusterRoleBindingProps": {
+		"roleRef":  "RoleRef",
+		"subjects": "[]Subject",
+	},
+	"KubeStorageClassProps": {
+		"parameters":        "map",
+		"allowedTopologies": "[]TopologySelectorTerm",
+	},
+	"KubePodTemplateProps": {
+		"template": "PodTemplateSpec",
+	},
+	"KubeEndpointsProps": {
+		"subsets": "[]EndpointSubset",
+	},
+	"KubeEndpointSliceProps": {
+		"endpoints": "[]Endpoint",
+		"ports":     "[]EndpointPort",
+	},
+	"KubeRuntimeClassProps": {
+		"overhead":   "Overhead",
+		"scheduling": "Scheduling",
+	},
+	"KubeMutatingWebhookConfigurationProps": {
+		"webhooks": "[]MutatingWebhook",
+	},
+	"KubeValidatingWebhookConfigurationProps": {
+		"webhooks": "[]ValidatingWebhook",
+	},
+
+	"ObjectMeta": {
+		"labels":          "map",
+		"annotations":     "map",
+		"ownerReferences": "[]OwnerReference",
+		"managedFields":   "[]ManagedFieldsEntry",
+	},
+	"ManagedFieldsEntry": {
+		"fieldsV1": "json",
+	},
+	"LabelSelector": {
+		"matchLabels":      "map",
+		"matchExpressions": "[]LabelSelectorRequirement",
+	},
+	"AggregationRule": {
+		"clusterRoleSelectors": "[]LabelSelector",
+	},
+	"TopologySelectorTerm": {
+		"matchLabelExpressions": "[]TopologySelectorLabelRequirement",
+	},
+
+	"DeploymentSpec": {
+		"selector": "LabelSelector",
+		"template": "PodTemplateSpec",
+		"strategy": "DeploymentStrategy",
+	},
+	"DeploymentStrategy": {
+		"rollingUpdate": "RollingUpdateDeployment",
+	},
+	"RollingUpdateDeployment": {
+		"maxSurge":       "IntOrString",
+		"maxUnavailable": "IntOrString",
+	},
+	"StatefulSetSpec": {
+		"selector":                             "LabelSelector",
+		"template":                             "PodTemplateSpec",
+		"volumeClaimTemplates":                 "[]KubePersistentVolumeClaimProps",
+		"updateStrategy":                       "StatefulSetUpdateStrategy",
+		"persistentVolumeClaimRetentionPolicy": "StatefulSetPersistentVolumeClaimRetentionPolicy",
+	},
+	"StatefulSetUpdateStrategy": {
+		"rollingUpdate": "RollingUpdateStatefulSetStrategy",
+	},
+	"RollingUpdateStatefulSetStrategy": {
+		"maxUnavailable": "IntOrString",
+	},
+	"DaemonSetSpec": {
+		"selector":       "LabelSelector",
+		"template":       "PodTemplateSpec",
+		"updateStrategy": "DaemonSetUpdateStrategy",
+	},
+	"DaemonSetUpdateStrategy": {
+		"rollingUpdate": "RollingUpdateDaemonSet",
+	},
+	"RollingUpdateDaemonSet": {
+		"maxSurge":       "IntOrString",
+		"maxUnavailable": "IntOrString",
+	},
+	"ReplicaSetSpec": {
+		"selector": "LabelSelector",
+		"template": "PodTemplateSpec",
+	},
+	"JobSpec": {
+		"selector": "LabelSelector",
+		"template": "PodTemplateSpec",
+	},
+	"CronJobSpec": {
+		"jobTemplate": "JobTemplateSpec",
+	},
+	"JobTemplateSpec": {
+		"metadata": "ObjectMeta",
+		"spec":     "JobSpec",
+	},
+	"PodTemplateSpec": {
+		"metadata": "ObjectMeta",
+		"spec":     "PodSpec",
+	},
+	"ReplicationControllerSpec": {
+		"selector": "map",
+		"template": "PodTemplateSpec",
+	},
+
+	"PodSpec": {
+		"containers":                "[]Container",
+		"initContainers":            "[]Container",
+		"ephemeralContainers":       "[]EphemeralContainer",
+		"volumes":                   "[]Volume",
+		"affinity":                  "Affinity",
+		"tolerations":               "[]Toleration",
+		"securityContext":           "PodSecurityContext",
+		"imagePullSecrets":          "[]LocalObjectReference",
+		"nodeSelector":              "map",
+		"hostAliases":               "[]HostAlias",
+		"dnsConfig":                 "PodDnsConfig",
+		"readinessGates":            "[]PodReadinessGate",
+		"topologySpreadConstraints": "[]TopologySpreadConstraint",
+		"overhead":                  "map[Quantity]",
+		"os":                        "PodOs",
+		"schedulingGates":           "[]PodSchedulingGate",
+		"resourceClaims":            "[]PodResourceClaim",
+		"resources":                 "ResourceRequirements",
+	},
+	"Container": {
+		"ports":           "[]ContainerPort",
+		"env":             "[]EnvVar",
+		"envFrom":         "[]EnvFromSource",
+		"resources":       "ResourceRequirements",
+		"volumeMounts":    "[]VolumeMount",
+		"volumeDevices":   "[]VolumeDevice",
+		"livenessProbe":   "Probe",
+		"readinessProbe":  "Probe",
+		"startupProbe":    "Probe",
+		"lifecycle":       "Lifecycle",
+		"securityContext": "SecurityContext",
+		"resizePolicy":    "[]ContainerResizePolicy",
+	},
+	"EphemeralContainer": {
+		"ports":           "[]ContainerPort",
+		"env":             "[]EnvVar",
+		"envFrom":         "[]EnvFromSource",
+		"resources":       "ResourceRequirements",
+		"volumeMounts":    "[]VolumeMount",
+		"volumeDevices":   "[]VolumeDevice",
+		"livenessProbe":   "Probe",
+		"readinessProbe":  "Probe",
+		"startupProbe":    "Probe",
+		"lifecycle":       "Lifecycle",
+		"securityContext": "SecurityContext",
+		"resizePolicy":    "[]ContainerResizePolicy",
+	},
+	"ResourceRequirements": {
+		"limits":   "map[Quantity]",
+		"requests": "map[Quantity]",
+		"claims":   "[]ResourceClaim",
+	},
+	"EnvVar": {
+		"valueFrom": "EnvVarSource",
+	},
+	"EnvVarSource": {
+		"configMapKeyRef":  "ConfigMapKeySelector",
+		"secretKeyRef":     "SecretKeySelector",
+		"fieldRef":         "ObjectFieldSelector",
+		"resourceFieldRef": "ResourceFieldSelector",
+	},
+	"ResourceFieldSelector": {
+		"divisor": "Quantity",
+	},
+	"EnvFromSource": {
+		"configMapRef": "ConfigMapEnvSource",
+		"secretRef":    "SecretEnvSource",
+	},
+	"Probe": {
+		"exec":      "ExecAction",
+		"httpGet":   "HttpGetAction",
+		"tcpSocket": "TcpSocketAction",
+		"grpc":      "GrpcAction",
+	},
+	"HttpGetAction": {
+		"port":        "IntOrString",
+		"httpHeaders": "[]HttpHeader",
+	},
+	"TcpSocketAction": {
+		"port": "IntOrString",
+	},
+	"Lifecycle": {
+		"postStart": "LifecycleHandler",
+		"preStop":   "LifecycleHandler",
+	},
+	"LifecycleHandler": {
+		"exec":      "ExecAction",
+		"httpGet":   "HttpGetAction",
+		"tcpSocket": "TcpSocketAction",
+		"sleep":     "SleepAction",
+	},
+	"SecurityContext": {
+		"capabilities":    "Capabilities",
+		"seLinuxOptions":  "SeLinuxOptions",
+		"seccompProfile":  "SeccompProfile",
+		"appArmorProfile": "AppArmorProfile",
+		"windowsOptions":  "WindowsSecurityContextOptions",
+	},
+	"PodSecurityContext": {
+		"seLinuxOptions":  "SeLinuxOptions",
+		"seccompProfile":  "SeccompProfile",
+		"appArmorProfile": "AppArmorProfile",
+		"sysctls":         "[]Sysctl",
+		"windowsOptions":  "WindowsSecurityContextOptions",
+	},
+	"PodDnsConfig": {
+		"options": "[]PodDnsConfigOption",
+	},
+	"TopologySpreadConstraint": {
+		"labelSelector": "LabelSelector",
+	},
+
+	"Volume": {
+		"configMap":             "ConfigMapVolumeSource",
+		"secret":                "SecretVolumeSource",
+		"emptyDir":              "EmptyDirVolumeSource",
+		"hostPath":              "HostPathVolumeSource",
+		"persistentVolumeClaim": "PersistentVolumeClaimVolumeSource",
+		"projected":             "ProjectedVolumeSource",
+		"downwardAPI":           "DownwardApiVolumeSource",
+		"csi":                   "CsiVolumeSource",
+		"nfs":                   "NfsVolumeSource",
+		"ephemeral":             "EphemeralVolumeSource",
+		"image":                 "ImageVolumeSource",
+		"awsElasticBlockStore":  "AwsElasticBlockStoreVolumeSource",
+		"azureDisk":             "AzureDiskVolumeSource",
+		"azureFile":             "AzureFileVolumeSource",
+		"cephfs":                "CephFsVolumeSource",
+		"cinder":                "CinderVolumeSource",
+		"fc":                    "FcVolumeSource",
+		"flexVolume":            "FlexVolumeSource",
+		"flocker":               "FlockerVolumeSource",
+		"gcePersistentDisk":     "GcePersistentDiskVolumeSource",
+		"gitRepo":               "GitRepoVolumeSource",
+		"glusterfs":             "GlusterfsVolumeSource",
+		"iscsi":                 "IscsiVolumeSource",
+		"photonPersistentDisk":  "PhotonPersistentDiskVolumeSource",
+		"portworxVolume":        "PortworxVolumeSource",
+		"quobyte":               "QuobyteVolumeSource",
+		"rbd":                   "RbdVolumeSource",
+		"scaleIO":               "ScaleIoVolumeSource",
+		"storageos":             "StorageOsVolumeSource",
+		"vsphereVolume":         "VsphereVirtualDiskVolumeSource",
+	},
+	"ConfigMapVolumeSource": {
+		"items": "[]KeyToPath",
+	},
+	"SecretVolumeSource": {
+		"items": "[]KeyToPath",
+	},
+	"EmptyDirVolumeSource": {
+		"sizeLimit": "Quantity",
+	},
+	"ProjectedVolumeSource": {
+		"sources": "[]VolumeProjection",
+	},
+	"VolumeProjection": {
+		"configMap":           "ConfigMapProjection",
+		"secret":              "SecretProjection",
+		"downwardAPI":         "DownwardApiProjection",
+		"serviceAccountToken": "ServiceAccountTokenProjection",
+		"clusterTrustBundle":  "ClusterTrustBundleProjection",
+	},
+	"ClusterTrustBundleProjection": {
+		"labelSelector": "LabelSelector",
+	},
+	"ConfigMapProjection": {
+		"items": "[]KeyToPath",
+	},
+	"SecretProjection": {
+		"items": "[]KeyToPath",
+	},
+	"DownwardApiVolumeSource": {
+		"items": "[]DownwardApiVolumeFile",
+	},
+	"DownwardApiProjection": {
+		"items": "[]DownwardApiVolumeFile",
+	},
+	"DownwardApiVolumeFile": {
+		"fieldRef":         "ObjectFieldSelector",
+		"resourceFieldRef": "ResourceFieldSelector",
+	},
+	"CsiVolumeSource": {
+		"volumeAttributes":     "map",
+		"nodePublishSecretRef": "LocalObjectReference",
+	},
+	"EphemeralVolumeSource": {
+		"volumeClaimTemplate": "PersistentVolumeClaimTemplate",
+	},
+	"PersistentVolumeClaimTemplate": {
+		"metadata": "ObjectMeta",
+		"spec":     "PersistentVolumeClaimSpec",
+	},
+	"CephFsVolumeSource": {
+		"secretRef": "LocalObjectReference",
+	},
+	"CinderVolumeSource": {
+		"secretRef": "LocalObjectReference",
+	},
+	"FlexVolumeSource": {
+		"secretRef": "LocalObjectReference",
+		"options":   "map",
+	},
+	"IscsiVolumeSource": {
+		"secretRef": "LocalObjectReference",
+	},
+	"RbdVolumeSource": {
+		"secretRef": "LocalObjectReference",
+	},
+	"ScaleIoVolumeSource": {
+		"secretRef": "LocalObjectReference",
+	},
+	"StorageOsVolumeSource": {
+		"secretRef": "LocalObjectReference",
+	},
+
+	"Affinity": {
+		"nodeAffinity":    "NodeAffinity",
+		"podAffinity":     "PodAffinity",
+		"podAntiAffinity": "PodAntiAffinity",
+	},
+	"NodeAffinity": {
+		"requiredDuringSchedulingIgnoredDuringExecution":  "NodeSelector",
+		"preferredDuringSchedulingIgnoredDuringExecution": "[]PreferredSchedulingTerm",
+	},
+	"NodeSelector": {
+		"nodeSelectorTerms": "[]NodeSelectorTerm",
+	},
+	"NodeSelectorTerm": {
+		"matchExpressions": "[]NodeSelectorRequirement",
+		"matchFields":      "[]NodeSelectorRequirement",
+	},
+	"PreferredSchedulingTerm": {
+		"preference": "NodeSelectorTerm",
+	},
+	"PodAffinity": {
+		"requiredDuringSchedulingIgnoredDuringExecution":  "[]PodAffinityTerm",
+		"preferredDuringSchedulingIgnoredDuringExecution": "[]WeightedPodAffinityTerm",
+	},
+	"PodAntiAffinity": {
+		"requiredDuringSchedulingIgnoredDuringExecution":  "[]PodAffinityTerm",
+		"preferredDuringSchedulingIgnoredDuringExecution": "[]WeightedPodAffinityTerm",
+	},
+	"PodAffinityTerm": {
+		"labelSelector":     "LabelSelector",
+		"namespaceSelector": "LabelSelector",
+	},
+	"WeightedPodAffinityTerm": {
+		"podAffinityTerm": "PodAffinityTerm",
+	},
+
+	"KubePersistentVolumeClaimProps": {
+		"metadata": "ObjectMeta",
+		"spec":     "PersistentVolumeClaimSpec",
+	},
+	"PersistentVolumeClaimSpec": {
+		"selector":      "LabelSelector",
+		"resources":     "ResourceRequirements",
+		"dataSource":    "TypedLocalObjectReference",
+		"dataSourceRef": "TypedLocalObjectReference",
+	},
+	"PersistentVolumeSpec": {
+		"capacity":             "map[Quantity]",
+		"claimRef":             "ObjectReference",
+		"nodeAffinity":         "VolumeNodeAffinity",
+		"csi":                  "CsiPersistentVolumeSource",
+		"hostPath":             "HostPathVolumeSource",
+		"local":                "LocalVolumeSource",
+		"nfs":                  "NfsVolumeSource",
+		"awsElasticBlockStore": "AwsElasticBlockStoreVolumeSource",
+		"azureDisk":            "AzureDiskVolumeSource",
+		"azureFile":            "AzureFilePersistentVolumeSource",
+		"cephfs":               "CephFsPersistentVolumeSource",
+		"cinder":               "CinderPersistentVolumeSource",
+		"fc":                   "FcVolumeSource",
+		"flexVolume":           "FlexPersistentVolumeSource",
+		"flocker":              "FlockerVolumeSource",
+		"gcePersistentDisk":    "GcePersistentDiskVolumeSource",
+		"glusterfs":            "GlusterfsPersistentVolumeSource",
+		"iscsi":                "IscsiPersistentVolumeSource",
+		"photonPersistentDisk": "PhotonPersistentDiskVolumeSource",
+		"portworxVolume":       "PortworxVolumeSource",
+		"quobyte":              "QuobyteVolumeSource",
+		"rbd":                  "RbdPersistentVolumeSource",
+		"scaleIO":              "ScaleIoPersistentVolumeSource",
+		"storageos":            "StorageOsPersistentVolumeSource",
+		"vsphereVolume":        "VsphereVirtualDiskVolumeSource",
+	},
+	"VolumeNodeAffinity": {
+		"required": "NodeSelector",
+	},
+	"CsiPersistentVolumeSource": {
+		"volumeAttributes":           "map",
+		"controllerExpandSecretRef":  "SecretReference",
+		"controllerPublishSecretRef": "SecretReference",
+		"nodeExpandSecretRef":        "SecretReference",
+		"nodePublishSecretRef":       "SecretReference",
+		"nodeStageSecretRef":         "SecretReference",
+	},
+	"CephFsPersistentVolumeSource": {
+		"secretRef": "SecretReference",
+	},
+	"CinderPersistentVolumeSource": {
+		"secretRef": "SecretReference",
+	},
+	"FlexPersistentVolumeSource": {
+		"secretRef": "SecretReference",
+		"options":   "map",
+	},
+	"IscsiPersistentVolumeSource": {
+		"secretRef": "SecretReference",
+	},
+	"RbdPersistentVolumeSource": {
+		"secretRef": "SecretReference",
+	},
+	"ScaleIoPersistentVolumeSource": {
+		"secretRef": "SecretReference",
+	},
+	"StorageOsPersistentVolumeSource": {
+		"secretRef": "ObjectReference",
+	},
+
+	"ResourceQuotaSpec": {
+		"hard":          "map[Quantity]",
+		"scopeSelector": "ScopeSelector",
+	},
+	"ScopeSelector": {
+		"matchExpressions": "[]ScopedResourceSelectorRequirement",
+	},
+	"LimitRangeSpec": {
+		"limits": "[]LimitRangeItem",
+	},
+	"LimitRangeItem": {
+		"default":              "map[Quantity]",
+		"defaultRequest":       "map[Quantity]",
+		"max":                  "map[Quantity]",
+		"min":                  "map[Quantity]",
+		"maxLimitRequestRatio": "map[Quantity]",
+	},
+
+	"EndpointSubset": {
+		"addresses":         "[]EndpointAddress",
+		"notReadyAddresses": "[]EndpointAddress",
+		"ports":             "[]EndpointPort",
+	},
+	"EndpointAddress": {
+		"targetRef": "ObjectReference",
+	},
+	"Endpoint": {
+		"conditions":         "EndpointConditions",
+		"hints":              "EndpointHints",
+		"targetRef":          "ObjectReference",
+		"deprecatedTopology": "map",
+	},
+	"EndpointHints": {
+		"forZones": "[]ForZone",
+	},
+
+	"ServiceSpec": {
+		"ports":                 "[]ServicePort",
+		"selector":              "map",
+		"sessionAffinityConfig": "SessionAffinityConfig",
+	},
+	"ServicePort": {
+		"targetPort": "IntOrString",
+	},
+	"SessionAffinityConfig": {
+		"clientIP": "ClientIpConfig",
+	},
+
+	"IngressSpec": {
+		"backend":        "IngressBackend",
+		"defaultBackend": "IngressBackend",
+		"rules":          "[]IngressRule",
+		"tls":            "[]IngressTls",
+	},
+	"IngressRule": {
+		"http": "HttpIngressRuleValue",
+	},
+	"HttpIngressRuleValue": {
+		"paths": "[]HttpIngressPath",
+	},
+	"HttpIngressPath": {
+		"backend": "IngressBackend",
+	},
+	"IngressBackend": {
+		"service":     "IngressServiceBackend",
+		"resource":    "TypedLocalObjectReference",
+		"servicePort": "IntOrString",
+	},
+	"IngressServiceBackend": {
+		"port": "ServiceBackendPort",
+	},
+	"IngressClassSpec": {
+		"parameters": "IngressClassParametersReference",
+	},
+
+	"NetworkPolicySpec": {
+		"podSelector": "LabelSelector",
+		"ingress":     "[]NetworkPolicyIngressRule",
+		"egress":      "[]NetworkPolicyEgressRule",
+	},
+	"NetworkPolicyIngressRule": {
+		"from":  "[]NetworkPolicyPeer",
+		"ports": "[]NetworkPolicyPort",
+	},
+	"NetworkPolicyEgressRule": {
+		"to":    "[]NetworkPolicyPeer",
+		"ports": "[]NetworkPolicyPort",
+	},
+	"NetworkPolicyPeer": {
+		"podSelector":       "LabelSelector",
+		"namespaceSelector": "LabelSelector",
+		"ipBlock":           "IpBlock",
+	},
+	"NetworkPolicyPort": {
+		"port": "IntOrString",
+	},
+
+	"HorizontalPodAutoscalerSpec": {
+		"scaleTargetRef": "CrossVersionObjectReference",
+		"metrics":        "[]MetricSpec",
+		"behavior":       "HorizontalPodAutoscalerBehavior",
+	},
+	"HorizontalPodAutoscalerBehavior": {
+		"scaleUp":   "HpaScalingRules",
+		"scaleDown": "HpaScalingRules",
+	},
+	"HpaScalingRules": {
+		"policies": "[]HpaScalingPolicy",
+	},
+	"MetricSpec": {
+		"resource":          "ResourceMetricSource",
+		"containerResource": "ContainerResourceMetricSource",
+		"pods":              "PodsMetricSource",
+		"object":            "ObjectMetricSource",
+		"external":          "ExternalMetricSource",
+	},
+	"ResourceMetricSource": {
+		"target": "MetricTarget",
+	},
+	"ContainerResourceMetricSource": {
+		"target": "MetricTarget",
+	},
+	"PodsMetricSource": {
+		"metric": "MetricIdentifier",
+		"target": "MetricTarget",
+	},
+	"ObjectMetricSource": {
+		"describedObject": "CrossVersionObjectReference",
+		"metric":          "MetricIdentifier",
+		"target":          "MetricTarget",
+	},
+	"ExternalMetricSource": {
+		"metric": "MetricIdentifier",
+		"target": "MetricTarget",
+	},
+	"MetricIdentifier": {
+		"selector": "LabelSelector",
+	},
+	"MetricTarget": {
+		"value":        "Quantity",
+		"averageValue": "Quantity",
+	},
+
+	"MutatingWebhook": {
+		"clientConfig":      "WebhookClientConfig",
+		"namespaceSelector": "LabelSelector",
+		"objectSelector":    "LabelSelector",
+		"rules":             "[]RuleWithOperations",
+		"matchConditions":   "[]MatchCondition",
+	},
+	"ValidatingWebhook": {
+		"clientConfig":      "WebhookClientConfig",
+		"namespaceSelector": "LabelSelector",
+		"objectSelector":    "LabelSelector",
+		"rules":             "[]RuleWithOperations",
+		"matchConditions":   "[]MatchCondition",
+	},
+	"WebhookClientConfig": {
+		"service": "ServiceReference",
+	},
+
+	"Overhead": {
+		"podFixed": "map[Quantity]",
+	},
+	"Scheduling": {
+		"nodeSelector": "map",
+		"tolerations":  "[]Toleration",
+	},
+
+	"PodDisruptionBudgetSpec": {
+		"selector":       "LabelSelector",
+		"minAvailable":   "IntOrString",
+		"maxUnavailable": "IntOrString",
+	},
+}
+
+// fieldType returns the type of property key of struct parent holding value.
+// Properties that are not listed in k8sStructs are not known to hold a
+// struct: maps and lists of maps are of unknownType rather than a struct the
+// bindings may not declare, and everything else keeps the type of its value.
+func fieldType(parent, key string, value *node) typeRef {
+	if t, ok := k8sStructs[parent][key]; ok {
+		return parseTypeRef(t)
+	}
+
+	if strings.HasPrefix(parent, "Kube") && strings.HasSuffix(parent, "Props") {
+		switch key {
+		case "metadata":
+			return typeRef{kind: structType, name: "ObjectMeta"}
+		case "spec":
+			kind := strings.TrimSuffix(strings.TrimPrefix(parent, "Kube"), "Props")
+			return typeRef{kind: structType, name: kind + "Spec"}
+		}
+	}
+
+	switch value.kind {
+	case mapNode:
+		return typeRef{kind: unknownType}
+	case listNode:
+		if len(value.items) > 0 && value.items[0].kind == mapNode {
+			return typeRef{kind: unknownType}
+		}
+	}
+
+	return typeRef{kind: inferredType}
+}
+
+// parseTypeRef parses a type as written in k8sStructs.
+func parseTypeRef(t string) typeRef {
+	switch {
+	case t == "map":
+		return typeRef{kind: mapType, elem: &typeRef{kind: inferredType}}
+	case strings.HasPrefix(t, "map["):
+		elem := parseTypeRef(strings.TrimSuffix(strings.TrimPrefix(t, "map["), "]"))
+		return typeRef{kind: mapType, elem: &elem}
+	case strings.HasPrefix(t, "[]"):
+		elem := parseTypeRef(strings.TrimPrefix(t, "[]"))
+		return typeRef{kind: listType, elem: &elem}
+	case t == "Quantity":
+		return typeRef{kind: quantityType}
+	case t == "IntOrString":
+		return typeRef{kind: intOrStringType}
+	case t == "json":
+		return typeRef{kind: jsonType}
+	}
+
+	return typeRef{kind: structType, name: t}
+}
+
+// jsiiName returns the property name cdk8s import generates for the field
+// key, which lowercases acronyms, e.g. clusterIP becomes clusterIp.
+func jsiiName(key string) string {
+	name := pyLowerUpper.ReplaceAllString(key, "${1}_${2}")
+	name = pyUpperWord.ReplaceAllString(name, "${1}_${2}")
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return key
+	}
+
+	for i, w := range words {
+		w = strings.ToLower(w)
+		if i > 0 {
+			w = upperFirst(w)
+		}
+		words[i] = w
+	}
+
+	return strings.Join(words, "")
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,162 @@
+package kube2cdk8s
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// typescript renders r as a cdk8s TypeScript construct.
+func typescript(r *resource) string {
+	var b strings.Builder
+	s := r.scope
+
+	b.WriteString(commentBlock(TypeScript, r.comments))
+	if r.declare {
+		fmt.Fprintf(&b, "const %s = ", r.variable)
+	}
+	if s.apiObject {
+		// ApiObjectProps takes any other field as is
+		s.cdk8s["ApiObject"] = true
+		props, rest := apiObjectProps(r)
+		props.fields = append(props.fields, rest...)
+
+		fmt.Fprintf(&b, "new ApiObject(this, %s, ", quote(r.id))
+		tsValue(&b, props, 0)
+	} else {
+		fmt.Fprintf(&b, "new %s.%s(this, %s, ", s.module, s.className(), quote(r.id))
+		tsTyped(&b, s, typeRef{kind: structType, name: s.propsType()}, r.props(), 0)
+	}
+	b.WriteString(");\n")
+
+	return b.String()
+}
+
+// tsValue writes n as a TypeScript literal indented at level.
+func tsValue(b *strings.Builder, n *node, level int) {
+	switch n.kind {
+	case nullNode:
+		b.WriteString("null")
+
+	case stringNode:
+		if strings.Contains(n.value, "\n") {
+			b.WriteString(tsTemplate(n.value))
+		} else {
+			b.WriteString(quote(n.value))
+		}
+
+	case mapNode:
+		tsObject(b, n, level, tsKey, func(f *field, level int) {
+			tsValue(b, f.value, level)
+		})
+
+	case listNode:
+		writeList(b, TypeScript, n.items, level, func(item *node, level int) {
+			tsValue(b, item, level)
+		})
+
+	case callNode:
+		b.WriteString(n.value + "(")
+		for i, arg := range n.items {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			tsValue(b, arg, level)
+		}
+		b.WriteString(")")
+
+	default:
+		b.WriteString(n.value)
+	}
+}
+
+// tsTyped writes n as a TypeScript value of type t declared in scope s. It
+// writes the same literals as tsValue, except that the values of union types
+// such as Quantity go through their factories.
+func tsTyped(b *strings.Builder, s *scope, t typeRef, n *node, level int) {
+	switch {
+	case t.kind == structType && n.kind == mapNode:
+		tsObject(b, n, level, tsProperty, func(f *field, level int) {
+			tsTyped(b, s, s.fieldType(t.name, f.key, f.value), f.value, level)
+		})
+
+	case t.kind == listType && n.kind == listNode:
+		writeList(b, TypeScript, n.items, level, func(item *node, level int) {
+			tsTyped(b, s, *t.elem, item, level)
+		})
+
+	case t.kind == mapType && n.kind == mapNode:
+		tsObject(b, n, level, tsKey, func(f *field, level int) {
+			tsTyped(b, s, *t.elem, f.value, level)
+		})
+
+	case t.kind == quantityType && isScalar(n):
+		tsUnion(b, s.module, "Quantity", n)
+
+	case t.kind == intOrStringType && isScalar(n):
+		tsUnion(b, s.module, "IntOrString", n)
+
+	default:
+		tsValue(b, n, level)
+	}
+}
+
+// tsObject writes the fields of a map node as an object literal, writing
+// each key with key and each value with value.
+func tsObject(b *strings.Builder, n *node, level int, key func(string) string, value func(f *field, level int)) {
+	if len(n.fields) == 0 {
+		b.WriteString("{}")
+		return
+	}
+
+	b.WriteString("{")
+	for _, f := range n.fields {
+		writeComments(b, TypeScript, f.value.comments, level+1)
+		b.WriteString("\n" + indent(level+1) + key(f.key) + ": ")
+		value(f, level+1)
+		b.WriteString(",")
+	}
+	b.WriteString("\n" + indent(level) + "}")
+}
+
+// tsUnion writes n through the fromNumber or fromString factory of a cdk8s
+// union class such as Quantity.
+func tsUnion(b *strings.Builder, module, class string, n *node) {
+	switch n.kind {
+	case intNode, floatNode:
+		fmt.Fprintf(b, "%s.%s.fromNumber(%s)", module, class, n.value)
+	default:
+		fmt.Fprintf(b, "%s.%s.fromString(%s)", module, class, quote(n.value))
+	}
+}
+
+// isScalar reports whether n is a string or a number parsed from the
+// manifest, as opposed to generated code.
+func isScalar(n *node) bool {
+	return n.kind == stringNode || n.kind == intNode || n.kind == floatNode
+}
+
+// tsKey returns key as an object literal key, quoting it when it is not a
+// valid identifier.
+func tsKey(key string) string {
+	if tsIdentifier.MatchString(key) {
+		return key
+	}
+
+	return quote(key)
+}
+
+// tsProperty returns key as the property of a struct generated by cdk8s
+// import.
+func tsProperty(key string) string {
+	return tsKey(jsiiName(key))
+}
+
+// tsTemplate returns s as a template literal so multi-line values such as
+// embedded config files stay readable.
+func tsTemplate(s string) string {
+	r := strings.NewReplacer("\\", "\\\\", "`", "\\`", "${", "\\${")
+	return "`" + r.Replace(s) + "`"
+}
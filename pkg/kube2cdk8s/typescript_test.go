@@ -0,0 +1,79 @@
+package kube2cdk8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bradleyjkemp/cupaloy"
+)
+
+func TestKube2CDK8STypeScriptUnions(t *testing.T) {
+
+	manifests := `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  strategy:
+    rollingUpdate:
+      maxSurge: 25%
+      maxUnavailable: 1
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx
+        ports:
+        - name: http
+          containerPort: 8080
+        resources:
+          requests:
+            cpu: 0.25
+            memory: 128Mi
+          limits:
+            cpu: 500m
+            memory: 1Gi
+        readinessProbe:
+          httpGet:
+            path: /healthz
+            port: http
+        livenessProbe:
+          tcpSocket:
+            port: 8080
+      volumes:
+      - name: cache
+        emptyDir:
+          sizeLimit: 1Gi
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  selector:
+    app: web
+  ports:
+  - port: 80
+    targetPort: http
+  - port: 8443
+    targetPort: 8443
+`
+
+	constructs, err := Convert(context.Background(), strings.NewReader(manifests), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cupaloy.Snapshot(Join(constructs, TypeScript))
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
@@ -0,0 +1,152 @@
+package kube2cdk8s
+
+import (
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// reservedWords are the identifiers of each language a variable cannot be
+// named: its keywords and the names the constructs are generated next to in
+// a chart. Go keywords are looked up with go/token.
+var reservedWords = map[Language]map[string]bool{
+	TypeScript: {
+		"arguments": true, "await": true, "break": true, "case": true, "catch": true,
+		"class": true, "const": true, "continue": true, "debugger": true, "default": true,
+		"delete": true, "do": true, "else": true, "enum": true, "eval": true,
+		"export": true, "extends": true, "false": true, "finally": true, "for": true,
+		"function": true, "id": true, "if": true, "implements": true, "import": true,
+		"in": true, "instanceof": true, "interface": true, "let": true, "new": true,
+		"null": true, "overrides": true, "package": true, "private": true, "props": true,
+		"protected": true, "public": true, "return": true, "scope": true, "static": true, "super": true, "switch": true,
+		"this": true, "throw": true, "true": true, "try": true, "typeof": true,
+		"var": true, "void": true, "while": true, "with": true, "yield": true,
+	},
+	Python: {
+		"id": true, "scope": true, "self": true,
+	},
+	Go: {
+		"cdk8s": true, "chart": true, "constructs": true, "id": true, "jsii": true,
+		"scope": true,
+	},
+	Java: {
+		"_": true, "abstract": true, "assert": true, "boolean": true, "break": true,
+		"byte": true, "case": true, "catch": true, "char": true, "class": true,
+		"const": true, "continue": true, "default": true, "do": true, "double": true,
+		"else": true, "enum": true, "extends": true, "false": true, "final": true,
+		"finally": true, "float": true, "for": true, "goto": true, "id": true,
+		"if": true, "implements": true, "import": true, "instanceof": true, "int": true,
+		"interface": true, "long": true, "native": true, "new": true, "null": true,
+		"package": true, "private": true, "protected": true, "public": true, "record": true,
+		"return": true, "scope": true, "short": true, "static": true, "strictfp": true,
+		"super": true, "switch": true, "synchronized": true, "this": true, "throw": true,
+		"throws": true, "transient": true, "true": true, "try": true, "var": true,
+		"void": true, "volatile": true, "while": true, "yield": true,
+	},
+	CSharp: {
+		"abstract": true, "as": true, "base": true, "bool": true, "break": true,
+		"byte": true, "case": true, "catch": true, "char": true, "checked": true,
+		"class": true, "const": true, "continue": true, "decimal": true, "default": true,
+		"delegate": true, "do": true, "double": true, "else": true, "enum": true,
+		"event": true, "explicit": true, "extern": true, "false": true, "finally": true,
+		"fixed": true, "float": true, "for": true, "foreach": true, "goto": true,
+		"id": true, "if": true, "implicit": true, "in": true, "int": true,
+		"interface": true, "internal": true, "is": true, "lock": true, "long": true,
+		"namespace": true, "new": true, "null": true, "object": true, "operator": true,
+		"out": true, "override": true, "params": true, "private": true, "protected": true,
+		"public": true, "readonly": true, "ref": true, "return": true, "sbyte": true,
+		"scope": true, "sealed": true, "short": true, "sizeof": true, "stackalloc": true,
+		"static": true, "string": true, "struct": true, "switch": true, "this": true,
+		"throw": true, "true": true, "try": true, "typeof": true, "uint": true,
+		"ulong": true, "unchecked": true, "unsafe": true, "ushort": true, "using": true,
+		"virtual": true, "void": true, "volatile": true, "while": true,
+	},
+}
+
+// reserved reports whether name cannot be given to a variable in language.
+func reserved(language Language, name string) bool {
+	switch language {
+	case Go:
+		if token.IsKeyword(name) {
+			return true
+		}
+	case Python:
+		if pyKeywords[name] {
+			return true
+		}
+	}
+
+	return reservedWords[language][name]
+}
+
+// variableName returns the variable holding the construct of the object of
+// kind called name in language: the words of the name followed by the
+// kind, in camelCase or in snake_case for Python, e.g. apiDeployment. Any
+// character that cannot start or continue an identifier separates words,
+// so system:controller:foo gives systemControllerFooClusterRole.
+func variableName(name, kind string, language Language) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return r >= unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r))
+	})
+
+	var v strings.Builder
+	for i, w := range words {
+		if i == 0 {
+			v.WriteString(strings.ToLower(w[:1]) + w[1:])
+		} else {
+			v.WriteString(upperFirst(w))
+		}
+	}
+	if v.Len() == 0 {
+		v.WriteString(strings.ToLower(kind[:1]) + kind[1:])
+	} else {
+		v.WriteString(kind)
+	}
+
+	variable := v.String()
+	if unicode.IsDigit(rune(variable[0])) {
+		variable = "_" + variable
+	}
+	if language == Python {
+		variable = pyName(variable)
+	}
+	if reserved(language, variable) {
+		variable += "_"
+	}
+
+	return variable
+}
+
+// assignVariables names the variable of every resource in language, and
+// has it declared when Options.Variables is set. A name given to an earlier
+// resource or to a module the constructs are imported from is numbered
+// until it is unique.
+func (opts Options) assignVariables(resources []*resource, language Language) {
+	used := map[string]bool{plusAlias: true}
+	if alias, err := opts.importAlias(); err == nil {
+		used[alias] = true
+	}
+	for _, m := range opts.Modules {
+		used[m.Alias] = true
+	}
+
+	separator := ""
+	if language == Python {
+		separator = "_"
+	}
+
+	for _, r := range resources {
+		if r == nil {
+			continue
+		}
+
+		name := variableName(r.name, r.kind, language)
+		r.variable = name
+		for i := 2; used[r.variable]; i++ {
+			r.variable = name + separator + strconv.Itoa(i)
+		}
+		used[r.variable] = true
+		r.declare = opts.Variables
+	}
+}
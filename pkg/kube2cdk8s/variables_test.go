@@ -0,0 +1,153 @@
+package kube2cdk8s
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bradleyjkemp/cupaloy"
+)
+
+const variableResources = `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: system:controller:foo
+rules:
+- apiGroups: [""]
+  resources: [pods]
+  verbs: [get]
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app.v2
+spec:
+  selector:
+    matchLabels:
+      app: my-app
+  template:
+    metadata:
+      labels:
+        app: my-app
+    spec:
+      containers:
+      - name: app
+        image: my-app:2
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-app.v2
+  namespace: prod
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-app.v2
+  namespace: staging
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: 2048-game
+size: large
+`
+
+func TestVariables(t *testing.T) {
+	for _, language := range []Language{TypeScript, Python, Go, Java, CSharp} {
+		opts := Options{Language: language, Variables: true, APIObjects: []string{"Widget"}}
+		constructs, err := Convert(context.Background(), strings.NewReader(variableResources), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = cupaloy.SnapshotMulti(string(language), Join(constructs, language))
+		if err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
+
+func TestVariablesChart(t *testing.T) {
+	opts := Options{Variables: true, APIObjects: []string{"Widget"}}
+	constructs, err := Convert(context.Background(), strings.NewReader(variableResources), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chart, err := Chart("", constructs, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the chart compiles with noUnusedLocals
+	for _, c := range constructs {
+		if !strings.Contains(chart, "void "+c.Variable+";") {
+			t.Errorf("%s is declared and not used:\n%s", c.Variable, chart)
+		}
+	}
+
+	err = cupaloy.Snapshot(chart)
+	if err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestVariableNames(t *testing.T) {
+	tests := map[Language][]string{
+		TypeScript: {"systemControllerFooClusterRole", "myAppV2Deployment", "myAppV2ConfigMap", "myAppV2ConfigMap2", "_2048GameWidget"},
+		Python:     {"system_controller_foo_cluster_role", "my_app_v2_deployment", "my_app_v2_config_map", "my_app_v2_config_map_2", "_2048_game_widget"},
+	}
+
+	for language, want := range tests {
+		constructs, err := Convert(context.Background(), strings.NewReader(variableResources), Options{Language: language, Variables: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var variables []string
+		for _, c := range constructs {
+			variables = append(variables, c.Variable)
+		}
+		if !reflect.DeepEqual(variables, want) {
+			t.Errorf("%s: got variables %v, want %v", language, variables, want)
+		}
+	}
+
+	constructs, err := Convert(context.Background(), strings.NewReader(variableResources), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range constructs {
+		if c.Variable != "" || strings.Contains(c.Code, "const ") {
+			t.Errorf("%s %s is assigned to a variable without Variables:\n%s", c.Kind, c.Name, c.Code)
+		}
+	}
+}
+
+func TestReservedVariables(t *testing.T) {
+	tests := []struct {
+		language Language
+		name     string
+		kind     string
+		want     string
+	}{
+		{CSharp, "", "Namespace", "namespace_"},
+		{TypeScript, "", "Namespace", "namespace"},
+		{Java, "", "Package", "package_"},
+		{Python, "", "Class", "class_"},
+		{Python, "", "Self", "self_"},
+		{Go, "", "Chart", "chart_"},
+		{Go, "", "Type", "type_"},
+		{TypeScript, "", "Scope", "scope_"},
+		{TypeScript, "名前", "Secret", "secret"},
+	}
+
+	for _, tt := range tests {
+		if got := variableName(tt.name, tt.kind, tt.language); got != tt.want {
+			t.Errorf("%s %q %s: got %q, want %q", tt.language, tt.name, tt.kind, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package kube2cdk8s
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// versionSuffix returns the suffix cdk8s import appends to the classes and
+// structs of an API version: none for v1, the version in PascalCase
+// otherwise, e.g. V1Beta1 for v1beta1 and V2 for v2.
+func versionSuffix(version string) string {
+	if version == "" || version == "v1" {
+		return ""
+	}
+
+	suffix := strings.ToUpper(version[:1]) + version[1:]
+	for _, stage := range []string{"alpha", "beta"} {
+		suffix = strings.Replace(suffix, stage, upperFirst(stage), 1)
+	}
+
+	return suffix
+}
+
+// apiVersionOf returns the version of apiVersion without its group.
+func apiVersionOf(apiVersion string) string {
+	return apiVersion[strings.LastIndex(apiVersion, "/")+1:]
+}
+
+// versionedStructs lists, for each API group, the structs its versions
+// declare besides the ones named after a kind, such as IngressSpec. cdk8s
+// import suffixes them like the classes, so a v1beta1 Ingress is made of
+// IngressSpecV1Beta1 and HttpIngressPathV1Beta1.
+var versionedStructs = map[string][]string{
+	"admissionregistration.k8s.io": {"MutatingWebhook", "ValidatingWebhook", "WebhookClientConfig", "ServiceReference", "RuleWithOperations", "MatchCondition"},
+	"apiextensions.k8s.io":         {"CustomResourceValidation", "CustomResourceConversion", "CustomResourceSubresources", "CustomResourceSubresourceScale", "CustomResourceColumnDefinition", "JsonSchemaProps", "ExternalDocumentation", "WebhookClientConfig", "ServiceReference"},
+	"apps":                         {"RollingUpdateDeployment", "RollingUpdateDaemonSet", "RollingUpdateStatefulSetStrategy", "RollbackConfig"},
+	"autoscaling":                  {"CrossVersionObjectReference", "MetricSpec", "MetricTarget", "MetricIdentifier", "ResourceMetricSource", "ContainerResourceMetricSource", "PodsMetricSource", "ObjectMetricSource", "ExternalMetricSource", "HpaScalingRules", "HpaScalingPolicy"},
+	"batch":                        {"JobTemplateSpec"},
+	"discovery.k8s.io":             {"Endpoint", "EndpointConditions", "EndpointHints", "EndpointPort", "ForZone"},
+	"extensions":                   {"HttpIngressRuleValue", "HttpIngressPath", "RollingUpdateDeployment", "RollingUpdateDaemonSet", "RollbackConfig"},
+	"networking.k8s.io":            {"HttpIngressRuleValue", "HttpIngressPath", "ServiceBackendPort"},
+	"node.k8s.io":                  {"Overhead", "Scheduling"},
+	"policy":                       {"AllowedHostPath", "AllowedCsiDriver", "AllowedFlexVolume", "FsGroupStrategyOptions", "HostPortRange", "IdRange", "RunAsUserStrategyOptions", "RunAsGroupStrategyOptions", "SeLinuxStrategyOptions", "SupplementalGroupsStrategyOptions", "RuntimeClassStrategyOptions"},
+	"rbac.authorization.k8s.io":    {"PolicyRule", "RoleRef", "Subject", "AggregationRule"},
+	"storage.k8s.io":               {"VolumeAttachmentSource"},
+}
+
+// isVersioned reports whether struct name is declared by the version of the
+// resource of s, and so carries its suffix.
+func (s *scope) isVersioned(name string) bool {
+	if s.suffix == "" {
+		return false
+	}
+
+	if strings.HasPrefix(name, s.kind) {
+		return true
+	}
+
+	return contains(versionedStructs[s.group], name)
+}
+
+// servedVersion is an API version of some kinds and the Kubernetes minor
+// versions serving it.
+type servedVersion struct {
+	apiVersion string
+	kinds      []string
+
+	// since is the first minor version serving it, 0 if every supported
+	// version does.
+	since int
+
+	// removed is the first minor version that no longer serves it, 0 if
+	// none.
+	removed int
+
+	// replacement is the API version to migrate to once it is removed.
+	replacement string
+}
+
+// servedVersions lists the API versions of the builtin kinds that were
+// added or removed since Kubernetes 1.16, after the Kubernetes deprecated API
+// migration guide.
+var servedVersions = []servedVersion{
+	{apiVersion: "extensions/v1beta1", kinds: []string{"Deployment", "DaemonSet", "ReplicaSet"}, removed: 16, replacement: "apps/v1"},
+	{apiVersion: "apps/v1beta1", kinds: []string{"Deployment", "StatefulSet", "ControllerRevision"}, removed: 16, replacement: "apps/v1"},
+	{apiVersion: "apps/v1beta2", kinds: []string{"Deployment", "DaemonSet", "ReplicaSet", "StatefulSet", "ControllerRevision"}, removed: 16, replacement: "apps/v1"},
+	{apiVersion: "extensions/v1beta1", kinds: []string{"NetworkPolicy"}, removed: 16, replacement: "networking.k8s.io/v1"},
+	{apiVersion: "extensions/v1beta1", kinds: []string{"PodSecurityPolicy"}, removed: 16, replacement: "policy/v1beta1"},
+
+	{apiVersion: "admissionregistration.k8s.io/v1beta1", kinds: []string{"MutatingWebhookConfiguration", "ValidatingWebhookConfiguration"}, removed: 22, replacement: "admissionregistration.k8s.io/v1"},
+	{apiVersion: "admissionregistration.k8s.io/v1", kinds: []string{"MutatingWebhookConfiguration", "ValidatingWebhookConfiguration"}, since: 16},
+	{apiVersion: "apiextensions.k8s.io/v1beta1", kinds: []string{"CustomResourceDefinition"}, removed: 22, replacement: "apiextensions.k8s.io/v1"},
+	{apiVersion: "apiextensions.k8s.io/v1", kinds: []string{"CustomResourceDefinition"}, since: 16},
+	{apiVersion: "apiregistration.k8s.io/v1beta1", kinds: []string{"APIService"}, removed: 22, replacement: "apiregistration.k8s.io/v1"},
+	{apiVersion: "certificates.k8s.io/v1beta1", kinds: []string{"CertificateSigningRequest"}, removed: 22, replacement: "certificates.k8s.io/v1"},
+	{apiVersion: "certificates.k8s.io/v1", kinds: []string{"CertificateSigningRequest"}, since: 19},
+	{apiVersion: "coordination.k8s.io/v1beta1", kinds: []string{"Lease"}, removed: 22, replacement: "coordination.k8s.io/v1"},
+	{apiVersion: "extensions/v1beta1", kinds: []string{"Ingress"}, removed: 22, replacement: "networking.k8s.io/v1"},
+	{apiVersion: "networking.k8s.io/v1beta1", kinds: []string{"Ingress", "IngressClass"}, removed: 22, replacement: "networking.k8s.io/v1"},
+	{apiVersion: "networking.k8s.io/v1", kinds: []string{"Ingress", "IngressClass"}, since: 19},
+	{apiVersion: "rbac.authorization.k8s.io/v1beta1", kinds: []string{"ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding"}, removed: 22, replacement: "rbac.authorization.k8s.io/v1"},
+	{apiVersion: "scheduling.k8s.io/v1beta1", kinds: []string{"PriorityClass"}, removed: 22, replacement: "scheduling.k8s.io/v1"},
+	{apiVersion: "storage.k8s.io/v1beta1", kinds: []string{"CSIDriver", "CSINode", "StorageClass", "VolumeAttachment"}, removed: 22, replacement: "storage.k8s.io/v1"},
+	{apiVersion: "storage.k8s.io/v1", kinds: []string{"CSINode"}, since: 17},
+	{apiVersion: "storage.k8s.io/v1", kinds: []string{"CSIDriver"}, since: 18},
+
+	{apiVersion: "batch/v1beta1", kinds: []string{"CronJob"}, removed: 25, replacement: "batch/v1"},
+	{apiVersion: "batch/v1", kinds: []string{"CronJob"}, since: 21},
+	{apiVersion: "discovery.k8s.io/v1beta1", kinds: []string{"EndpointSlice"}, removed: 25, replacement: "discovery.k8s.io/v1"},
+	{apiVersion: "discovery.k8s.io/v1", kinds: []string{"EndpointSlice"}, since: 21},
+	{apiVersion: "events.k8s.io/v1beta1", kinds: []string{"Event"}, removed: 25, replacement: "events.k8s.io/v1"},
+	{apiVersion: "events.k8s.io/v1", kinds: []string{"Event"}, since: 19},
+	{apiVersion: "autoscaling/v2beta1", kinds: []string{"HorizontalPodAutoscaler"}, removed: 25, replacement: "autoscaling/v2"},
+	{apiVersion: "autoscaling/v2beta2", kinds: []string{"HorizontalPodAutoscaler"}, removed: 26, replacement: "autoscaling/v2"},
+	{apiVersion: "autoscaling/v2", kinds: []string{"HorizontalPodAutoscaler"}, since: 23},
+	{apiVersion: "policy/v1beta1", kinds: []string{"PodDisruptionBudget"}, removed: 25, replacement: "policy/v1"},
+	{apiVersion: "policy/v1", kinds: []string{"PodDisruptionBudget"}, since: 21},
+	{apiVersion: "policy/v1beta1", kinds: []string{"PodSecurityPolicy"}, removed: 25},
+	{apiVersion: "node.k8s.io/v1beta1", kinds: []string{"RuntimeClass"}, removed: 25, replacement: "node.k8s.io/v1"},
+	{apiVersion: "node.k8s.io/v1", kinds: []string{"RuntimeClass"}, since: 20},
+
+	{apiVersion: "flowcontrol.apiserver.k8s.io/v1beta1", kinds: []string{"FlowSchema", "PriorityLevelConfiguration"}, removed: 26, replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	{apiVersion: "flowcontrol.apiserver.k8s.io/v1beta2", kinds: []string{"FlowSchema", "PriorityLevelConfiguration"}, removed: 29, replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	{apiVersion: "flowcontrol.apiserver.k8s.io/v1beta3", kinds: []string{"FlowSchema", "PriorityLevelConfiguration"}, since: 26, removed: 32, replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	{apiVersion: "flowcontrol.apiserver.k8s.io/v1", kinds: []string{"FlowSchema", "PriorityLevelConfiguration"}, since: 29},
+	{apiVersion: "storage.k8s.io/v1beta1", kinds: []string{"CSIStorageCapacity"}, removed: 27, replacement: "storage.k8s.io/v1"},
+	{apiVersion: "storage.k8s.io/v1", kinds: []string{"CSIStorageCapacity"}, since: 24},
+}
+
+var kubernetesVersionPattern = regexp.MustCompile(`^v?1\.(\d+)(\.\d+)?$`)
+
+// kubernetesMinor returns the minor version of the Kubernetes version set in
+// opts, 0 if none is.
+func (opts Options) kubernetesMinor() (int, error) {
+	if opts.KubernetesVersion == "" {
+		return 0, nil
+	}
+
+	m := kubernetesVersionPattern.FindStringSubmatch(opts.KubernetesVersion)
+	if m == nil {
+		return 0, fmt.Errorf("invalid kubernetes version %q, expected e.g. 1.22", opts.KubernetesVersion)
+	}
+
+	return strconv.Atoi(m[1])
+}
+
+// checkAPIVersion checks that the Kubernetes version set in opts serves the
+// API version of r, so that its class is part of the k8s bindings imported
+// for it.
+func (opts Options) checkAPIVersion(r *resource) error {
+	minor, err := opts.kubernetesMinor()
+	if err != nil || minor == 0 {
+		return err
+	}
+
+	for _, v := range servedVersions {
+		if v.apiVersion != r.apiVersion || !contains(v.kinds, r.kind) {
+			continue
+		}
+
+		if v.since != 0 && minor < v.since {
+			return fmt.Errorf("%s %s requires kubernetes 1.%d, targeting %s", r.apiVersion, r.kind, v.since, opts.KubernetesVersion)
+		}
+
+		if v.removed != 0 && minor >= v.removed {
+			msg := fmt.Sprintf("%s %s was removed in kubernetes 1.%d, targeting %s", r.apiVersion, r.kind, v.removed, opts.KubernetesVersion)
+			if v.replacement != "" {
+				msg += ", migrate it to " + v.replacement
+			}
+			return errors.New(msg)
+		}
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,127 @@
+package kube2cdk8s
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bradleyjkemp/cupaloy"
+)
+
+const versionedResources = `
+apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  backend:
+    serviceName: default-http-backend
+    servicePort: http
+  rules:
+  - host: example.com
+    http:
+      paths:
+      - path: /
+        backend:
+          serviceName: web
+          servicePort: 80
+---
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: legacy
+spec:
+  backend:
+    serviceName: legacy
+    servicePort: 8080
+  tls:
+  - hosts: [legacy.example.com]
+    secretName: legacy-tls
+---
+apiVersion: autoscaling/v2beta2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: web
+  minReplicas: 2
+  maxReplicas: 10
+  metrics:
+  - type: Resource
+    resource:
+      name: cpu
+      target:
+        type: Utilization
+        averageUtilization: 80
+`
+
+func TestVersionedClasses(t *testing.T) {
+	for _, language := range []Language{TypeScript, Python, Go, Java, CSharp} {
+		constructs, err := Convert(context.Background(), strings.NewReader(versionedResources), Options{Language: language})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = cupaloy.SnapshotMulti(string(language), Join(constructs, language))
+		if err != nil {
+			t.Error(err.Error())
+		}
+	}
+}
+
+func TestVersionSuffix(t *testing.T) {
+	tests := map[string]string{
+		"":         "",
+		"v1":       "",
+		"v2":       "V2",
+		"v1beta1":  "V1Beta1",
+		"v2beta2":  "V2Beta2",
+		"v1alpha1": "V1Alpha1",
+	}
+
+	for version, want := range tests {
+		if got := versionSuffix(version); got != want {
+			t.Errorf("%q: got %q, want %q", version, got, want)
+		}
+	}
+}
+
+func TestKubernetesVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		err     string
+	}{
+		{"", ""},
+		{"1.21", ""},
+		{"v1.21.3", ""},
+		{"1.22", "networking.k8s.io/v1beta1 Ingress was removed in kubernetes 1.22, targeting 1.22, migrate it to networking.k8s.io/v1"},
+		{"1.26", "autoscaling/v2beta2 HorizontalPodAutoscaler was removed in kubernetes 1.26"},
+	}
+
+	for _, tt := range tests {
+		_, err := Convert(context.Background(), strings.NewReader(versionedResources), Options{KubernetesVersion: tt.version})
+		if tt.err == "" {
+			if err != nil {
+				t.Errorf("%q: %v", tt.version, err)
+			}
+			continue
+		}
+
+		var errs Errors
+		if !errors.As(err, &errs) {
+			t.Fatalf("%q: expected Errors, got %v", tt.version, err)
+		}
+		if !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%q: expected %q in %v", tt.version, tt.err, err)
+		}
+	}
+
+	_, err := Convert(context.Background(), strings.NewReader(versionedResources), Options{KubernetesVersion: "latest"})
+	if err == nil {
+		t.Error("expected an error for an invalid version")
+	}
+}